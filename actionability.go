@@ -0,0 +1,170 @@
+package marionette
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Actionability checks, performed in this order by WaitActionable.
+const (
+	CHECK_ATTACHED   = "attached"
+	CHECK_VISIBLE    = "visible"
+	CHECK_ENABLED    = "enabled"
+	CHECK_STABLE     = "stable"
+	CHECK_HIT_TARGET = "hit target"
+)
+
+var (
+	clickChecks = []string{CHECK_ATTACHED, CHECK_VISIBLE, CHECK_ENABLED, CHECK_STABLE, CHECK_HIT_TARGET}
+	inputChecks = []string{CHECK_ATTACHED, CHECK_VISIBLE, CHECK_ENABLED}
+)
+
+// actionabilityPollInterval is the delay between two actionability checks.
+const actionabilityPollInterval = 50 * time.Millisecond
+
+// actionabilityScript resolves with null when the element passes all the
+// checks, or with the first failed check.
+const actionabilityScript = `
+let [el, checks] = arguments;
+let resolve = arguments[arguments.length - 1];
+let fail = (check, message) => resolve({check, message});
+let describe = (node) => node.localName +
+	(node.id ? "#" + node.id : "") +
+	(typeof node.className == "string" && node.className.trim() ? "." + node.className.trim().split(/\s+/).join(".") : "");
+
+if (checks.includes("attached") && !el.isConnected) {
+	return fail("attached", "element is not attached to the document");
+}
+
+if (checks.includes("visible")) {
+	let style = getComputedStyle(el);
+	let rect = el.getBoundingClientRect();
+	if (style.visibility != "visible") {
+		return fail("visible", "element has visibility:" + style.visibility);
+	}
+	if (rect.width == 0 || rect.height == 0) {
+		return fail("visible", "element has an empty size");
+	}
+}
+
+if (checks.includes("enabled")) {
+	if (el.disabled || el.closest("fieldset[disabled]")) {
+		return fail("enabled", "element is disabled");
+	}
+	if (el.getAttribute("aria-disabled") == "true") {
+		return fail("enabled", "element is aria-disabled");
+	}
+}
+
+let afterFrames = (f) => requestAnimationFrame(() => requestAnimationFrame(f));
+
+let hitTest = () => {
+	if (!checks.includes("hit target")) {
+		return resolve(null);
+	}
+	el.scrollIntoView({block: "nearest", inline: "nearest"});
+	let rect = el.getBoundingClientRect();
+	let x = rect.left + rect.width / 2, y = rect.top + rect.height / 2;
+	let hit = el.ownerDocument.elementFromPoint(x, y);
+	if (hit == null) {
+		return fail("hit target", "element center (" + x + ", " + y + ") is outside of the viewport");
+	}
+	if (hit != el && !el.contains(hit)) {
+		return fail("hit target", "element is covered by " + describe(hit) + " at (" + x + ", " + y + ")");
+	}
+	resolve(null);
+};
+
+if (checks.includes("stable")) {
+	let before = el.getBoundingClientRect();
+	afterFrames(() => {
+		let after = el.getBoundingClientRect();
+		if (before.x != after.x || before.y != after.y || before.width != after.width || before.height != after.height) {
+			return fail("stable", "element is moving");
+		}
+		hitTest();
+	});
+} else {
+	hitTest();
+}
+`
+
+// ActionabilityError tells why an element was not actionable in time.
+type ActionabilityError struct {
+	// Check is the failed check, one of the CHECK_* constants.
+	Check   string
+	Message string
+	// Err is the error that ended the wait.
+	Err error
+}
+
+func (e *ActionabilityError) Error() string {
+	return fmt.Sprintf("element not actionable: %s check failed: %s", e.Check, e.Message)
+}
+
+func (e *ActionabilityError) Unwrap() error {
+	return e.Err
+}
+
+// SetAutoWait enables, with a non-zero timeout, the actionability checks
+// before WebElement's Click, SendKeys and Clear. Click waits for the element
+// to be attached, visible, enabled, stable and not covered by another
+// element; SendKeys and Clear skip the last two checks.
+func (c *Client) SetAutoWait(timeout time.Duration) {
+	c.autoWait = timeout
+}
+
+// checkActionable runs the checks once, returning the first failed one.
+func (e *WebElement) checkActionable(checks []string) (*ActionabilityError, error) {
+	var r *Response
+	err := e.heal(func() (err error) {
+		r, err = e.c.ExecuteAsyncScript(actionabilityScript, []any{e, checks}, false)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	var out struct {
+		Value *ActionabilityError `json:"value"`
+	}
+	err = json.Unmarshal([]byte(r.Value), &out)
+	return out.Value, err
+}
+
+// WaitActionable waits until the element passes the given checks, all of
+// them if none is given. It returns an ActionabilityError describing the
+// last failed check when the element is not actionable in time.
+func (e *WebElement) WaitActionable(ctx context.Context, timeout time.Duration, checks ...string) error {
+	if len(checks) == 0 {
+		checks = clickChecks
+	}
+
+	// lazy elements may not have an id yet
+	desc := e.LocatorString()
+	if desc == "" {
+		desc = e.id
+	}
+
+	var failed *ActionabilityError
+	err := Wait(e.c).Context(ctx).For(timeout).Poll(actionabilityPollInterval).
+		Describe("element "+desc+" is actionable").
+		run("", func(ctx context.Context) (bool, error) {
+			var err error
+			failed, err = e.checkActionable(checks)
+			return failed == nil && err == nil, err
+		})
+	if err != nil && failed != nil {
+		failed.Err = err
+		return failed
+	}
+	return err
+}
+
+func (e *WebElement) autoWait(checks []string) error {
+	if e.c.autoWait == 0 {
+		return nil
+	}
+	return e.WaitActionable(context.Background(), e.c.autoWait, checks...)
+}
@@ -0,0 +1,44 @@
+package marionette
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// required test in sequential main client test: client_test.go
+func ActionabilityTest(t *testing.T) {
+	navigateLocal("actionability.html")
+
+	client.SetAutoWait(5 * time.Second)
+	defer client.SetAutoWait(0)
+
+	button, err := client.FindElement(By(ID), "late-button")
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	err = button.Click()
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if text := button.Text(); text != "clicked" {
+		t.Fatalf("button was not clicked: %v", text)
+	}
+
+	covered, err := client.FindElement(By(ID), "covered-button")
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	err = covered.WaitActionable(context.Background(), 500*time.Millisecond)
+	var ae *ActionabilityError
+	if !errors.As(err, &ae) {
+		t.Fatalf("expected an ActionabilityError, got %#v", err)
+	}
+	if ae.Check != CHECK_HIT_TARGET {
+		t.Fatalf("expected the hit target check to fail, got %v", ae)
+	}
+	t.Log(ae)
+}
@@ -11,6 +11,7 @@ type Capabilities struct {
 	AcceptSslCerts                bool
 	TakesElementScreenshot        bool
 	TakesScreenshot               bool
+	StrictFileInteractability     bool `json:"strictFileInteractability,omitempty"`
 	Proxy                         any
 	Platform                      string
 	XULappId                      string
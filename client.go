@@ -23,7 +23,13 @@ type Client struct {
 	SessionId    string
 	Capabilities Capabilities
 
-	tr *Transport
+	tr       *Transport
+	window   string        // current window handle, empty when unknown
+	autoWait time.Duration // actionability timeout, see SetAutoWait
+	frames   []frameRef    // path from the top-level document to the current frame
+
+	noHealing  bool
+	onRelocate func(e *WebElement, oldId string, err error)
 }
 
 func NewClient() *Client {
@@ -151,6 +157,7 @@ func (c *Client) Navigate(url string) (*Response, error) {
 	if err != nil {
 		return nil, err
 	}
+	c.frames = nil
 
 	return r, nil
 }
@@ -183,19 +190,31 @@ func (c *Client) URL() (string, error) {
 // Refresh the page.
 func (c *Client) Refresh() error {
 	_, err := c.tr.Send("WebDriver:Refresh", nil)
-	return err
+	if err != nil {
+		return err
+	}
+	c.frames = nil
+	return nil
 }
 
 // Back go back in navigation history
 func (c *Client) Back() error {
 	_, err := c.tr.Send("WebDriver:Back", nil)
-	return err
+	if err != nil {
+		return err
+	}
+	c.frames = nil
+	return nil
 }
 
 // Forward go forward in navigation history
 func (c *Client) Forward() error {
 	_, err := c.tr.Send("WebDriver:Forward", nil)
-	return err
+	if err != nil {
+		return err
+	}
+	c.frames = nil
+	return nil
 }
 
 // SetContext Sets the context of the subsequent commands to be either "chrome" or "content".
@@ -243,7 +262,12 @@ func (c *Client) GetWindowHandles() ([]string, error) {
 // SwitchToWindow switch to specific window.
 func (c *Client) SwitchToWindow(name string) error {
 	_, err := c.tr.Send("WebDriver:SwitchToWindow", map[string]any{"focus": true, "handle": name})
-	return err
+	if err != nil {
+		return err
+	}
+	c.window = name
+	c.frames = nil
+	return nil
 }
 
 // GetWindowRect gets window position and size
@@ -326,19 +350,25 @@ func (c *Client) FullscreenWindow() (rect *WindowRect, err error) {
 // new top-level browsing context should be a private window.
 // Defaults to false.
 //
-// return {"handle": string, "type": string}
-// Handle and type of the new browsing context.
-func (c *Client) NewWindow(focus bool, typ string, private bool) (*Response, error) {
-	//TODO: would be nice if we could create a Window struct and return that struct instead of the Response object
-	return c.tr.Send("WebDriver:NewWindow", map[string]any{
+// The new window is not made current, use Window.Switch or the Window
+// methods to act on it.
+func (c *Client) NewWindow(focus bool, typ string, private bool) (*Window, error) {
+	w := &Window{Private: private, c: c}
+	err := c.tr.SendAndDecode(w, "WebDriver:NewWindow", map[string]any{
 		"focus":   focus,
 		"type":    typ,
 		"private": private,
 	})
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
 }
 
 // CloseWindow closes current window.
 func (c *Client) CloseWindow() (*Response, error) {
+	c.window = ""
+	c.frames = nil
 	return c.tr.Send("WebDriver:CloseWindow", nil)
 }
 
@@ -358,6 +388,8 @@ func (c *Client) CloseChromeWindow() (*Response, error) {
 }
 
 // SwitchToFrame switch to frame - strategies: By(ID), By(NAME) or name only.
+//
+// See also SwitchToFrameIndex, SwitchToFrameElement and SwitchToTopFrame.
 func (c *Client) SwitchToFrame(by By, value string) error {
 	//with current marionette implementation we have to find the element first and send the switchToFrame
 	//command with the UUID, else it wont work.
@@ -367,14 +399,19 @@ func (c *Client) SwitchToFrame(by By, value string) error {
 		return err
 	}
 
-	_, err = c.tr.Send("WebDriver:SwitchToFrame", map[string]any{"element": frame.Id(), "focus": true})
-	return err
+	return c.SwitchToFrameElement(frame)
 }
 
 // SwitchToParentFrame switch to parent frame
 func (c *Client) SwitchToParentFrame() error {
 	_, err := c.tr.Send("WebDriver:SwitchToParentFrame", nil)
-	return err
+	if err != nil {
+		return err
+	}
+	if len(c.frames) != 0 {
+		c.frames = c.frames[: len(c.frames)-1 : len(c.frames)-1]
+	}
+	return nil
 }
 
 // AddCookie Adds a cookie
@@ -411,8 +448,8 @@ func (c *Client) DeleteAllCookies() error {
 // WEB ELEMENTS //
 //////////////////
 
-func isElementEnabled(c *Client, id string) bool {
-	r, err := c.tr.Send("WebDriver:IsElementEnabled", map[string]any{"id": id})
+func isElementEnabled(e *WebElement) bool {
+	r, err := e.send("WebDriver:IsElementEnabled", nil)
 	if err != nil {
 		return false
 	}
@@ -420,8 +457,8 @@ func isElementEnabled(c *Client, id string) bool {
 	return strings.Contains(r.Value, "\"value\":true")
 }
 
-func isElementSelected(c *Client, id string) bool {
-	r, err := c.tr.Send("WebDriver:IsElementSelected", map[string]any{"id": id})
+func isElementSelected(e *WebElement) bool {
+	r, err := e.send("WebDriver:IsElementSelected", nil)
 	if err != nil {
 		return false
 	}
@@ -429,8 +466,8 @@ func isElementSelected(c *Client, id string) bool {
 	return strings.Contains(r.Value, "\"value\":true")
 }
 
-func isElementDisplayed(c *Client, id string) bool {
-	r, err := c.tr.Send("WebDriver:IsElementDisplayed", map[string]any{"id": id})
+func isElementDisplayed(e *WebElement) bool {
+	r, err := e.send("WebDriver:IsElementDisplayed", nil)
 	if err != nil {
 		return false
 	}
@@ -438,8 +475,8 @@ func isElementDisplayed(c *Client, id string) bool {
 	return strings.Contains(r.Value, "\"value\":true")
 }
 
-func getElementTagName(c *Client, id string) string {
-	r, err := c.tr.Send("WebDriver:GetElementTagName", map[string]any{"id": id})
+func getElementTagName(e *WebElement) string {
+	r, err := e.send("WebDriver:GetElementTagName", nil)
 	if err != nil {
 		return ""
 	}
@@ -450,8 +487,8 @@ func getElementTagName(c *Client, id string) string {
 	return d["value"]
 }
 
-func getElementText(c *Client, id string) string {
-	r, err := c.tr.Send("WebDriver:GetElementText", map[string]any{"id": id})
+func getElementText(e *WebElement) string {
+	r, err := e.send("WebDriver:GetElementText", nil)
 	if err != nil {
 		return ""
 	}
@@ -490,7 +527,11 @@ func (c *Client) findElements(by By, value string, startNode *string) ([]*WebEle
 
 // FindElements Find elements using the indicated search strategy.
 func (c *Client) FindElements(by By, value string) ([]*WebElement, error) {
-	return c.findElements(by, value, nil)
+	es, err := c.findElements(by, value, nil)
+	for i, e := range es {
+		e.loc = &elementLocator{by: by, value: value, index: i}
+	}
+	return es, err
 }
 
 func (c *Client) findElement(by By, value string, startNode *string) (*WebElement, error) {
@@ -510,7 +551,12 @@ func (c *Client) findElement(by By, value string, startNode *string) (*WebElemen
 
 // FindElement Find an element using the indicated search strategy.
 func (c *Client) FindElement(by By, value string) (*WebElement, error) {
-	return c.findElement(by, value, nil)
+	e, err := c.findElement(by, value, nil)
+	if err != nil {
+		return nil, err
+	}
+	e.loc = &elementLocator{by: by, value: value, index: -1}
+	return e, nil
 }
 
 // GetActiveElement Returns the page's active element.
@@ -532,17 +578,26 @@ func (c *Client) PageSource() (string, error) {
 	return out.Value, err
 }
 
-func convertScriptArgs(args []any) {
+// convertScriptArgs replaces the elements of args by their web element
+// reference, as scripts expect, locating lazy elements.
+func convertScriptArgs(args []any) error {
 	for i, arg := range args {
 		if e, ok := arg.(*WebElement); ok {
-			args[i] = e.Id() // TODO: convert properly
+			id, err := e.locatedId()
+			if err != nil {
+				return err
+			}
+			args[i] = map[string]string{WEBDRIVER_ELEMENT_KEY: id}
 		}
 	}
+	return nil
 }
 
 // ExecuteScript Execute JS Script
 func (c *Client) ExecuteScript(script string, args []any, timeout time.Duration, newSandbox bool) (*Response, error) {
-	convertScriptArgs(args)
+	if err := convertScriptArgs(args); err != nil {
+		return nil, err
+	}
 	return c.tr.Send("WebDriver:ExecuteScript", map[string]any{
 		"scriptTimeout": int(timeout.Milliseconds()),
 		"script":        script,
@@ -551,15 +606,31 @@ func (c *Client) ExecuteScript(script string, args []any, timeout time.Duration,
 	})
 }
 
-// ExecuteAsyncScript Execute JS Script Async
-// TODO: Add missing arguments/options
+// ExecuteAsyncScript Execute JS Script Async, within the session script
+// timeout (see SetScriptTimeout)
 func (c *Client) ExecuteAsyncScript(script string, args []any, newSandbox bool) (*Response, error) {
-	convertScriptArgs(args)
-	return c.tr.Send("WebDriver:ExecuteAsyncScript", map[string]any{
+	return c.executeAsyncScript(script, args, nil, newSandbox)
+}
+
+// ExecuteAsyncScriptTimeout Execute JS Script Async, within timeout instead
+// of the session script timeout
+func (c *Client) ExecuteAsyncScriptTimeout(script string, args []any, timeout time.Duration, newSandbox bool) (*Response, error) {
+	return c.executeAsyncScript(script, args, &timeout, newSandbox)
+}
+
+func (c *Client) executeAsyncScript(script string, args []any, timeout *time.Duration, newSandbox bool) (*Response, error) {
+	if err := convertScriptArgs(args); err != nil {
+		return nil, err
+	}
+	params := map[string]any{
 		"script":     script,
 		"args":       args,
 		"newSandbox": newSandbox,
-	})
+	}
+	if timeout != nil {
+		params["scriptTimeout"] = int(timeout.Milliseconds())
+	}
+	return c.tr.Send("WebDriver:ExecuteAsyncScript", params)
 }
 
 // DismissAlert dismisses the dialog - like clicking No/Cancel
@@ -599,17 +670,11 @@ func (c *Client) Quit() (*Response, error) {
 }
 
 func (c *Client) takeScreenshot(startNode *string) ([]byte, error) {
-	var params map[string]string
-	if startNode == nil || *startNode == "" {
-		params = map[string]string{}
-	} else {
-		params = map[string]string{"id": *startNode}
+	opts := defaultScreenshotOptions
+	if startNode != nil && *startNode != "" {
+		opts.element = *startNode
 	}
-	var out struct {
-		Value []byte `json:"value"`
-	}
-	err := c.tr.SendAndDecode(&out, "WebDriver:TakeScreenshot", params)
-	return out.Value, err
+	return c.takeScreenshotWith(opts)
 }
 
 func (c *Client) takeScreenshotImage(startNode *string) (image.Image, error) {
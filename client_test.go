@@ -20,7 +20,7 @@ const (
 
 var client *Client
 
-func navigateLocal(page string) (*Response, error) {
+func localURL(page string) string {
 	pwd, err := os.Getwd()
 	if err != nil {
 		fmt.Println(err)
@@ -30,7 +30,11 @@ func navigateLocal(page string) (*Response, error) {
 	fmt.Println(pwd)
 
 	var schema = "file://" + pwd + "/" + TESTDATA_FOLDER + "/" + WWW_FOLDER + "/"
-	return client.Navigate(schema + page)
+	return schema + page
+}
+
+func navigateLocal(page string) (*Response, error) {
+	return client.Navigate(localURL(page))
 }
 
 func init() {
@@ -54,6 +58,14 @@ func TestInit(t *testing.T) {
 
 		t.Run("GetSessionCapabilitiesTest", GetSessionCapabilitiesTest)
 		t.Run("ScreenshotTest", ScreenshotTest)
+		t.Run("ScreenshotOptionsTest", ScreenshotOptionsTest)
+		t.Run("PrintPDFTest", PrintPDFTest)
+		t.Run("DownloadTest", DownloadTest)
+		t.Run("FetchTest", FetchTest)
+		t.Run("FetchTimeoutTest", FetchTimeoutTest)
+		t.Run("CookieJarTest", CookieJarTest)
+		t.Run("ScreencastTest", ScreencastTest)
+		t.Run("TraceTest", TraceTest)
 
 		t.Run("SetContextTest", SetContextTest)
 		t.Run("GetContextTest", GetContextTest)
@@ -72,20 +84,36 @@ func TestInit(t *testing.T) {
 		t.Run("ExecuteScriptWithoutFunctionTest", ExecuteScriptWithoutFunctionTest)
 		t.Run("ExecuteScriptTest", ExecuteScriptTest)
 		t.Run("ExecuteScriptWithArgsTest", ExecuteScriptWithArgsTest)
+		t.Run("ExecuteScriptElementArgTest", ExecuteScriptElementArgTest)
 
 		t.Run("ExecuteAsyncScriptWithArgsTest", ExecuteAsyncScriptWithArgsTest)
 
 		t.Run("GetTitleTest", GetTitleTest)
 
 		t.Run("FindElementTest", FindElementTest)
+		t.Run("LocatorTest", LocatorTest)
+		t.Run("QueryLocatorTest", QueryLocatorTest)
+		t.Run("PageObjectTest", PageObjectTest)
+		t.Run("ExtractTest", ExtractTest)
+		t.Run("TableTest", TableTest)
 
 		t.Run("SendKeysTest", SendKeysTest)
+		t.Run("SelectTest", SelectTest)
+		t.Run("FillFormTest", FillFormTest)
+		t.Run("UploadFilesTest", UploadFilesTest)
+		t.Run("ActionabilityTest", ActionabilityTest)
 		t.Run("FindElementsTest", FindElementsTest)
+		t.Run("SelfHealingTest", SelfHealingTest)
 
 		t.Run("NewWindowTest", NewWindowTest)
 		t.Run("WindowHandlesTest", WindowHandlesTest)
 		t.Run("CloseWindowTest", CloseWindowTest)
+		t.Run("WindowTest", WindowTest)
+		t.Run("WindowSwitchFrameTest", WindowSwitchFrameTest)
+		t.Run("WaitForNewWindowTest", WaitForNewWindowTest)
 		t.Run("SwitchToParentFrameTest", SwitchToParentFrameTest)
+		t.Run("FramesTest", FramesTest)
+		t.Run("FindElementInAnyFrameTest", FindElementInAnyFrameTest)
 
 		t.Run("NavigatorMethodsTest", NavigatorMethodsTest)
 
@@ -456,6 +484,38 @@ func ExecuteScriptWithArgsTest(t *testing.T) {
 	t.Log(r.Value)
 }
 
+func TestScriptArgs(t *testing.T) {
+	args := []any{1, &WebElement{id: "abc"}}
+	if err := convertScriptArgs(args); err != nil {
+		t.Fatal(err)
+	}
+
+	// a bare id would be a string in the script, not the element
+	ref, ok := args[1].(map[string]string)
+	if !ok || len(ref) != 1 || ref[WEBDRIVER_ELEMENT_KEY] != "abc" {
+		t.Fatalf("expected a web element reference, got %#v", args[1])
+	}
+	if args[0] != 1 {
+		t.Fatalf("unexpected argument %#v", args[0])
+	}
+}
+
+func ExecuteScriptElementArgTest(t *testing.T) {
+	navigateLocal("table.html")
+	element, err := client.FindElement(By(TAG_NAME), "table")
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	r, err := client.ExecuteScript("return arguments[0].localName;", []any{element}, TIMEOUT, false)
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if r.Value != `{"value":"table"}` {
+		t.Fatalf("expected the element in the script, got %s", r.Value)
+	}
+}
+
 func ExecuteAsyncScriptWithArgsTest(t *testing.T) {
 	script := "function testMyGoMarionetteClientArgs(a, b) { return a + b; }; " +
 		"let resolve = arguments[arguments.length - 1]; " +
@@ -1,5 +1,17 @@
 package marionette
 
+import (
+	"net/http"
+	"time"
+)
+
+// Cookie SameSite values.
+const (
+	SAME_SITE_STRICT = "Strict"
+	SAME_SITE_LAX    = "Lax"
+	SAME_SITE_NONE   = "None"
+)
+
 type Cookie struct {
 	Secure   bool   `json:"secure,omitempty"`
 	Expiry   uint   `json:"expiry,omitempty"`
@@ -8,4 +20,69 @@ type Cookie struct {
 	Name     string `json:"name"`
 	Path     string `json:"path,omitempty"`
 	Value    string `json:"value"`
+	SameSite string `json:"sameSite,omitempty"`
+}
+
+// HTTPCookie converts c to an http.Cookie.
+func (c Cookie) HTTPCookie() *http.Cookie {
+	hc := &http.Cookie{
+		Name:     c.Name,
+		Value:    c.Value,
+		Path:     c.Path,
+		Domain:   c.Domain,
+		Secure:   c.Secure,
+		HttpOnly: c.HttpOnly,
+	}
+	if c.Expiry != 0 {
+		hc.Expires = time.Unix(int64(c.Expiry), 0)
+	}
+	switch c.SameSite {
+	case SAME_SITE_STRICT:
+		hc.SameSite = http.SameSiteStrictMode
+	case SAME_SITE_LAX:
+		hc.SameSite = http.SameSiteLaxMode
+	case SAME_SITE_NONE:
+		hc.SameSite = http.SameSiteNoneMode
+	}
+	return hc
+}
+
+// CookieFromHTTP converts an http.Cookie to a Cookie. MaxAge takes
+// precedence over Expires, relatively to now; expired cookies have an
+// Expiry in the past.
+func CookieFromHTTP(hc *http.Cookie, now time.Time) Cookie {
+	c := Cookie{
+		Name:     hc.Name,
+		Value:    hc.Value,
+		Path:     hc.Path,
+		Domain:   hc.Domain,
+		Secure:   hc.Secure,
+		HttpOnly: hc.HttpOnly,
+	}
+	switch {
+	case hc.MaxAge < 0:
+		c.Expiry = 1
+	case hc.MaxAge > 0:
+		c.Expiry = uint(now.Unix()) + uint(hc.MaxAge)
+	case !hc.Expires.IsZero():
+		if exp := hc.Expires.Unix(); exp > 0 {
+			c.Expiry = uint(exp)
+		} else {
+			c.Expiry = 1
+		}
+	}
+	switch hc.SameSite {
+	case http.SameSiteStrictMode:
+		c.SameSite = SAME_SITE_STRICT
+	case http.SameSiteLaxMode:
+		c.SameSite = SAME_SITE_LAX
+	case http.SameSiteNoneMode:
+		c.SameSite = SAME_SITE_NONE
+	}
+	return c
+}
+
+// Expired returns true if c expired at now. Session cookies never expire.
+func (c Cookie) Expired(now time.Time) bool {
+	return c.Expiry != 0 && int64(c.Expiry) <= now.Unix()
 }
@@ -0,0 +1,223 @@
+package marionette
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CookieJar is an http.CookieJar backed by the browser session, so that an
+// http.Client shares the cookies of the browser, like its login session.
+//
+// WebDriver only gives access to the cookies of the current page: Cookies
+// returns those matching the requested URL, and SetCookies can only set
+// cookies for the domain of the current page. Other cookies are skipped, and
+// reported by Err.
+//
+//	httpClient := &http.Client{Jar: NewCookieJar(client)}
+type CookieJar struct {
+	c *Client
+
+	mu  sync.Mutex
+	err error
+}
+
+// NewCookieJar returns a CookieJar of the session of c.
+func NewCookieJar(c *Client) *CookieJar {
+	return &CookieJar{c: c}
+}
+
+// Err returns the last error that happened when reading or writing cookies,
+// as the http.CookieJar interface doesn't return errors, and clears it.
+func (j *CookieJar) Err() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	err := j.err
+	j.err = nil
+	return err
+}
+
+func (j *CookieJar) setErr(err error) {
+	j.mu.Lock()
+	j.err = err
+	j.mu.Unlock()
+}
+
+// SetCookies adds cookies received from u to the browser, and removes the
+// expired ones.
+func (j *CookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	if len(cookies) == 0 {
+		return
+	}
+	pageURL, err := j.c.URL()
+	if err != nil {
+		j.setErr(err)
+		return
+	}
+	page, err := url.Parse(pageURL)
+	if err != nil {
+		j.setErr(err)
+		return
+	}
+
+	now := time.Now()
+	for _, hc := range cookies {
+		c := CookieFromHTTP(hc, now)
+		if c.Path == "" || !strings.HasPrefix(c.Path, "/") {
+			c.Path = defaultCookiePath(u)
+		}
+
+		if c.Expired(now) {
+			if err = j.deleteCookie(c, page.Hostname()); err != nil {
+				j.setErr(err)
+			}
+			continue
+		}
+		// host-only cookies can only be set for the host of the current page,
+		// a domain would widen them to subdomains
+		if c.Domain == "" && !strings.EqualFold(u.Hostname(), page.Hostname()) {
+			j.setErr(fmt.Errorf("can't set host-only cookie %q of %s from a page of %s",
+				c.Name, u.Hostname(), page.Hostname()))
+			continue
+		}
+
+		if _, err = j.c.AddCookie(c); err != nil {
+			j.setErr(err)
+		}
+	}
+}
+
+// deleteCookie deletes the browser cookie with the name, domain and path of
+// c, if any. WebDriver deletes cookies by name, so the other cookies with
+// this name are added back.
+func (j *CookieJar) deleteCookie(c Cookie, pageHost string) error {
+	cookies, err := j.c.GetCookies()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	var others []Cookie
+	for _, b := range cookies {
+		if b.Name != c.Name {
+			continue
+		}
+		if sameCookie(b, c, pageHost) {
+			found = true
+		} else {
+			others = append(others, b)
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	if err, _ = j.c.DeleteCookie(c.Name); err != nil {
+		return err
+	}
+	for _, o := range others {
+		if !strings.HasPrefix(o.Domain, ".") {
+			o.Domain = "" // keep it host-only
+		}
+		if _, err = j.c.AddCookie(o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sameCookie returns true if the browser cookie b has the domain and path of
+// c, a host-only cookie of pageHost when it has no domain.
+func sameCookie(b, c Cookie, pageHost string) bool {
+	if b.Path != c.Path {
+		return false
+	}
+	if c.Domain == "" {
+		return strings.EqualFold(b.Domain, pageHost)
+	}
+	return strings.HasPrefix(b.Domain, ".") &&
+		strings.EqualFold(b.Domain[1:], strings.TrimPrefix(c.Domain, "."))
+}
+
+// Cookies returns the cookies of the browser to send to u.
+func (j *CookieJar) Cookies(u *url.URL) []*http.Cookie {
+	cookies, err := j.c.GetCookies()
+	if err != nil {
+		j.setErr(err)
+		return nil
+	}
+	return matchCookies(cookies, u, time.Now())
+}
+
+// matchCookies returns the cookies to send to u, longest paths first, as
+// specified by RFC 6265.
+func matchCookies(cookies []Cookie, u *url.URL, now time.Time) []*http.Cookie {
+	secure := u.Scheme == "https" || u.Scheme == "wss"
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	var matched []Cookie
+	for _, c := range cookies {
+		if c.Expired(now) || (c.Secure && !secure) {
+			continue
+		}
+		if !cookieDomainMatch(u.Hostname(), c.Domain) || !cookiePathMatch(path, c.Path) {
+			continue
+		}
+		matched = append(matched, c)
+	}
+	sort.SliceStable(matched, func(i, k int) bool { return len(matched[i].Path) > len(matched[k].Path) })
+
+	result := make([]*http.Cookie, len(matched))
+	for i, c := range matched {
+		result[i] = &http.Cookie{Name: c.Name, Value: c.Value}
+	}
+	return result
+}
+
+// cookieDomainMatch returns true if a cookie of domain must be sent to host.
+// Domain cookies start with a dot, others are host-only.
+func cookieDomainMatch(host, domain string) bool {
+	host = strings.ToLower(host)
+	domain = strings.ToLower(domain)
+	if domain == "" {
+		return false
+	}
+	if !strings.HasPrefix(domain, ".") {
+		return host == domain
+	}
+	domain = domain[1:]
+	if host == domain {
+		return true
+	}
+	return net.ParseIP(host) == nil && strings.HasSuffix(host, "."+domain)
+}
+
+// cookiePathMatch returns true if a cookie of cookiePath must be sent to
+// requests on path.
+func cookiePathMatch(path, cookiePath string) bool {
+	if cookiePath == "" || cookiePath == "/" || path == cookiePath {
+		return true
+	}
+	if !strings.HasPrefix(path, cookiePath) {
+		return false
+	}
+	return strings.HasSuffix(cookiePath, "/") || path[len(cookiePath)] == '/'
+}
+
+// defaultCookiePath returns the default path of cookies set by u.
+func defaultCookiePath(u *url.URL) string {
+	path := u.EscapedPath()
+	i := strings.LastIndex(path, "/")
+	if !strings.HasPrefix(path, "/") || i <= 0 {
+		return "/"
+	}
+	return path[:i]
+}
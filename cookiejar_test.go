@@ -0,0 +1,176 @@
+package marionette
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCookieJar(t *testing.T) {
+	t.Run("CookieConversionTest", CookieConversionTest)
+	t.Run("CookieMatchTest", CookieMatchTest)
+	t.Run("DefaultCookiePathTest", DefaultCookiePathTest)
+	t.Run("SameCookieTest", SameCookieTest)
+}
+
+func SameCookieTest(t *testing.T) {
+	hostOnly := Cookie{Name: "a", Domain: "example.com", Path: "/"}
+	domain := Cookie{Name: "a", Domain: ".example.com", Path: "/"}
+
+	for _, test := range []struct {
+		c        Cookie
+		expected [2]bool // host-only, domain
+	}{
+		{Cookie{Name: "a", Path: "/"}, [2]bool{true, false}},
+		{Cookie{Name: "a", Domain: "example.com", Path: "/"}, [2]bool{false, true}},
+		{Cookie{Name: "a", Domain: ".example.com", Path: "/"}, [2]bool{false, true}},
+		{Cookie{Name: "a", Path: "/app"}, [2]bool{false, false}},
+		{Cookie{Name: "a", Domain: "other.com", Path: "/"}, [2]bool{false, false}},
+	} {
+		got := [2]bool{sameCookie(hostOnly, test.c, "example.com"), sameCookie(domain, test.c, "example.com")}
+		if got != test.expected {
+			t.Fatalf("%+v: expected %v, got %v", test.c, test.expected, got)
+		}
+	}
+}
+
+func CookieConversionTest(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	c := CookieFromHTTP(&http.Cookie{
+		Name: "a", Value: "1", Path: "/app", Domain: "example.com",
+		Secure: true, HttpOnly: true, MaxAge: 60, SameSite: http.SameSiteLaxMode,
+	}, now)
+	expected := Cookie{
+		Name: "a", Value: "1", Path: "/app", Domain: "example.com",
+		Secure: true, HttpOnly: true, Expiry: 1700000060, SameSite: SAME_SITE_LAX,
+	}
+	if c != expected {
+		t.Fatalf("unexpected cookie %+v", c)
+	}
+
+	hc := c.HTTPCookie()
+	if hc.Name != "a" || hc.Path != "/app" || !hc.Expires.Equal(time.Unix(1700000060, 0)) ||
+		hc.SameSite != http.SameSiteLaxMode || !hc.Secure || !hc.HttpOnly {
+		t.Fatalf("unexpected http cookie %+v", hc)
+	}
+
+	if c = CookieFromHTTP(&http.Cookie{Name: "b", Expires: now.Add(time.Hour)}, now); c.Expiry != 1700003600 {
+		t.Fatalf("unexpected expiry %d", c.Expiry)
+	}
+	if c = CookieFromHTTP(&http.Cookie{Name: "b", MaxAge: -1}, now); !c.Expired(now) {
+		t.Fatal("expected an expired cookie")
+	}
+	if c = CookieFromHTTP(&http.Cookie{Name: "b"}, now); c.Expiry != 0 || c.Expired(now) {
+		t.Fatalf("expected a session cookie, got %+v", c)
+	}
+}
+
+func CookieMatchTest(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	cookies := []Cookie{
+		{Name: "host", Value: "1", Domain: "example.com", Path: "/"},
+		{Name: "domain", Value: "2", Domain: ".example.com", Path: "/"},
+		{Name: "deep", Value: "3", Domain: "example.com", Path: "/app/admin"},
+		{Name: "app", Value: "4", Domain: "example.com", Path: "/app"},
+		{Name: "secure", Value: "5", Domain: "example.com", Path: "/", Secure: true},
+		{Name: "expired", Value: "6", Domain: "example.com", Path: "/", Expiry: 1600000000},
+		{Name: "other", Value: "7", Domain: "other.com", Path: "/"},
+	}
+
+	names := func(rawURL string) (names []string) {
+		u, _ := url.Parse(rawURL)
+		for _, c := range matchCookies(cookies, u, now) {
+			names = append(names, c.Name)
+		}
+		return
+	}
+	equal := func(a, b []string) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	if n := names("http://example.com/app/admin/users"); !equal(n, []string{"deep", "app", "host", "domain"}) {
+		t.Fatalf("unexpected cookies %v", n)
+	}
+	if n := names("https://example.com/application"); !equal(n, []string{"host", "domain", "secure"}) {
+		t.Fatalf("unexpected cookies %v", n)
+	}
+	if n := names("http://www.example.com/"); !equal(n, []string{"domain"}) {
+		t.Fatalf("unexpected cookies %v", n)
+	}
+	if n := names("http://notexample.com/"); len(n) != 0 {
+		t.Fatalf("unexpected cookies %v", n)
+	}
+}
+
+func DefaultCookiePathTest(t *testing.T) {
+	for rawURL, expected := range map[string]string{
+		"http://example.com":            "/",
+		"http://example.com/":           "/",
+		"http://example.com/login":      "/",
+		"http://example.com/app/login":  "/app",
+		"http://example.com/app/admin/": "/app/admin",
+	} {
+		u, _ := url.Parse(rawURL)
+		if p := defaultCookiePath(u); p != expected {
+			t.Fatalf("%s: expected %q, got %q", rawURL, expected, p)
+		}
+	}
+}
+
+// required test in sequential main client test: client_test.go
+func CookieJarTest(t *testing.T) {
+	srv := echoServer(t)
+	if _, err := client.Navigate(srv.URL + "/"); err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	jar := NewCookieJar(client)
+	u, _ := url.Parse(srv.URL + "/echo")
+
+	// the browser session is shared with Go
+	httpClient := &http.Client{Jar: jar}
+	resp, err := httpClient.Get(srv.URL + "/echo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err = jar.Err(); err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if want := `"session":"s3cr3t"`; !strings.Contains(string(body), want) {
+		t.Fatalf("expected %s in %s", want, body)
+	}
+
+	// and cookies set from Go reach the browser
+	jar.SetCookies(u, []*http.Cookie{{Name: "from-go", Value: "yes", Path: "/", SameSite: http.SameSiteLaxMode}})
+	if err = jar.Err(); err != nil {
+		t.Fatalf("%#v", err)
+	}
+	found := false
+	for _, c := range jar.Cookies(u) {
+		found = found || (c.Name == "from-go" && c.Value == "yes")
+	}
+	if !found {
+		t.Fatal("cookie set from Go not found")
+	}
+
+	jar.SetCookies(u, []*http.Cookie{{Name: "from-go", MaxAge: -1}})
+	for _, c := range jar.Cookies(u) {
+		if c.Name == "from-go" {
+			t.Fatal("expired cookie not removed")
+		}
+	}
+}
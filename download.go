@@ -0,0 +1,117 @@
+package marionette
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// Download is a resource downloaded by the browser.
+type Download struct {
+	// URL is the final URL of the resource, after redirects.
+	URL string
+	// MIMEType is the media type of the resource, like "image/png".
+	MIMEType string
+	// Data are the original bytes of the resource.
+	Data []byte
+}
+
+// DownloadError is returned when a download failed.
+type DownloadError struct {
+	URL string
+	// Status is the HTTP status, 0 on network errors.
+	Status  int
+	Message string
+}
+
+func (e *DownloadError) Error() string {
+	return fmt.Sprintf("download of %s failed: %s", e.URL, e.Message)
+}
+
+// Download fetches src with the browser, with its cookies, and returns the
+// original bytes.
+//
+// The resource is first fetched from the current page, without side effects.
+// If the page is not allowed to read it (cross-origin resources without
+// CORS), it is fetched from a new background tab opened on src, which is
+// then closed; the original window and frame are restored. Other network
+// errors, like unknown hosts, are returned without opening a tab.
+//
+// The deadline of ctx bounds each fetch, instead of the session script
+// timeout; without deadline, the session script timeout applies.
+func (c *Client) Download(ctx context.Context, src string) (*Download, error) {
+	d, err := c.download(ctx, src)
+	if ferr, ok := err.(*FetchError); ok && ferr.Blocked {
+		return c.downloadInNewTab(ctx, src)
+	} else if ok {
+		return nil, &DownloadError{URL: src, Message: ferr.Message}
+	}
+	return d, err
+}
+
+func (c *Client) downloadInNewTab(ctx context.Context, src string) (d *Download, err error) {
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	orig, err := c.GetWindowHandle()
+	if err != nil {
+		return nil, err
+	}
+	frames := c.frames
+
+	tab, err := c.NewWindow(false, "tab", false)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		cerr := tab.Close()
+		if serr := c.SwitchToWindow(orig); serr != nil {
+			cerr = serr
+		} else if serr = c.switchToFramePath(frames); serr != nil {
+			cerr = serr
+		}
+		if err == nil && cerr != nil {
+			d, err = nil, cerr
+		}
+	}()
+
+	if _, err = tab.Navigate(src); err != nil {
+		return nil, err
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+	// the tab's document has the origin of src
+	d, err = c.download(ctx, src)
+	if ferr, ok := err.(*FetchError); ok {
+		err = &DownloadError{URL: src, Message: ferr.Message}
+	}
+	return d, err
+}
+
+// download fetches src from the current page.
+func (c *Client) download(ctx context.Context, src string) (*Download, error) {
+	r, err := c.fetch(ctx, &fetchRequest{Method: http.MethodGet, URL: src, Headers: [][2]string{}})
+	if err != nil {
+		return nil, err
+	}
+	if r.Status < 200 || r.Status > 299 {
+		return nil, &DownloadError{URL: src, Status: r.Status, Message: fmt.Sprintf("HTTP %d %s", r.Status, r.StatusText)}
+	}
+
+	data, err := base64.StdEncoding.DecodeString(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	d := &Download{URL: r.URL, Data: data}
+	for _, h := range r.Headers {
+		if strings.EqualFold(h[0], "Content-Type") {
+			d.MIMEType, _, _ = mime.ParseMediaType(h[1])
+		}
+	}
+	return d, nil
+}
@@ -0,0 +1,142 @@
+package marionette
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDownload(t *testing.T) {
+	t.Run("DownloadCanceledTest", DownloadCanceledTest)
+}
+
+func DownloadCanceledTest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.Download(ctx, "http://example.com/image.png"); err != context.Canceled {
+		t.Fatalf("expected the context error, got %v", err)
+	}
+}
+
+// imageServer serves a page on /, a PNG image on /image.png, and the same
+// image with CORS headers allowing credentials on /cors/image.png.
+func imageServer(t *testing.T) (*httptest.Server, []byte) {
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	img.Set(1, 1, color.NRGBA{R: 255, A: 255})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		io.WriteString(w, "<html><body><img src=\"/image.png\"></body></html>")
+	})
+	mux.HandleFunc("/image.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(data)
+	})
+	mux.HandleFunc("/cors/image.png", func(w http.ResponseWriter, r *http.Request) {
+		// a wildcard origin is refused with credentials
+		w.Header().Set("Access-Control-Allow-Origin", r.Header.Get("Origin"))
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+		w.Header().Set("Vary", "Origin")
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(data)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, data
+}
+
+// required test in sequential main client test: client_test.go
+func DownloadTest(t *testing.T) {
+	srv, data := imageServer(t)
+	// localhost is another origin than the 127.0.0.1 page
+	crossOrigin := strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+
+	if _, err := client.Navigate(srv.URL + "/"); err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	windows, err := client.GetWindowHandles()
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	tabs := 0
+	removeHook := client.tr.addHook(&commandHook{before: func(command string, _ any) any {
+		if command == "WebDriver:NewWindow" {
+			tabs++
+		}
+		return nil
+	}})
+	defer removeHook()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, test := range []struct {
+		url  string
+		tabs int
+	}{
+		{srv.URL + "/image.png", 0},          // same origin
+		{crossOrigin + "/cors/image.png", 0}, // CORS with credentials
+		{crossOrigin + "/image.png", 1},      // blocked, from a new tab
+	} {
+		tabs = 0
+		d, err := client.Download(ctx, test.url)
+		if err != nil {
+			t.Fatalf("%s: %#v", test.url, err)
+		}
+		if d.MIMEType != "image/png" || !bytes.Equal(d.Data, data) {
+			t.Fatalf("%s: unexpected download %s of %d bytes", test.url, d.MIMEType, len(d.Data))
+		}
+		if tabs != test.tabs {
+			t.Fatalf("%s: expected %d tabs to be opened, got %d", test.url, test.tabs, tabs)
+		}
+	}
+
+	img, err := client.DownloadImage(ctx, srv.URL+"/image.png")
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if img.Bounds().Dx() != 3 || img.Bounds().Dy() != 2 {
+		t.Fatalf("unexpected image size %v", img.Bounds())
+	}
+
+	// refused connections are not retried in a new tab
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	tabs = 0
+	_, err = client.Download(ctx, strings.Replace(closed.URL, "127.0.0.1", "localhost", 1)+"/image.png")
+	if _, ok := err.(*DownloadError); !ok || tabs != 0 {
+		t.Fatalf("expected a DownloadError without new tab, got %v and %d tabs", err, tabs)
+	}
+
+	_, err = client.Download(ctx, srv.URL+"/missing.png")
+	if derr, ok := err.(*DownloadError); !ok || derr.Status != http.StatusNotFound {
+		t.Fatalf("expected a 404 DownloadError, got %v", err)
+	}
+
+	after, err := client.GetWindowHandles()
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if len(after) != len(windows) {
+		t.Fatalf("expected %d windows, got %d", len(windows), len(after))
+	}
+	if url, _ := client.URL(); url != srv.URL+"/" {
+		t.Fatalf("the current page changed to %s", url)
+	}
+}
@@ -1,5 +1,39 @@
 package marionette
 
+import "errors"
+
+// Error types reported in DriverError.ErrorType.
+//
+// https://w3c.github.io/webdriver/#errors
+const (
+	ERROR_ELEMENT_CLICK_INTERCEPTED = "element click intercepted"
+	ERROR_ELEMENT_NOT_INTERACTABLE  = "element not interactable"
+	ERROR_INSECURE_CERTIFICATE      = "insecure certificate"
+	ERROR_INVALID_ARGUMENT          = "invalid argument"
+	ERROR_INVALID_COOKIE_DOMAIN     = "invalid cookie domain"
+	ERROR_INVALID_ELEMENT_STATE     = "invalid element state"
+	ERROR_INVALID_SELECTOR          = "invalid selector"
+	ERROR_INVALID_SESSION_ID        = "invalid session id"
+	ERROR_JAVASCRIPT_ERROR          = "javascript error"
+	ERROR_MOVE_TARGET_OUT_OF_BOUNDS = "move target out of bounds"
+	ERROR_NO_SUCH_ALERT             = "no such alert"
+	ERROR_NO_SUCH_COOKIE            = "no such cookie"
+	ERROR_NO_SUCH_ELEMENT           = "no such element"
+	ERROR_NO_SUCH_FRAME             = "no such frame"
+	ERROR_NO_SUCH_WINDOW            = "no such window"
+	ERROR_SCRIPT_TIMEOUT            = "script timeout"
+	ERROR_SESSION_NOT_CREATED       = "session not created"
+	ERROR_STALE_ELEMENT_REFERENCE   = "stale element reference"
+	ERROR_TIMEOUT                   = "timeout"
+	ERROR_UNABLE_TO_SET_COOKIE      = "unable to set cookie"
+	ERROR_UNABLE_TO_CAPTURE_SCREEN  = "unable to capture screen"
+	ERROR_UNEXPECTED_ALERT_OPEN     = "unexpected alert open"
+	ERROR_UNKNOWN_COMMAND           = "unknown command"
+	ERROR_UNKNOWN_ERROR             = "unknown error"
+	ERROR_UNKNOWN_METHOD            = "unknown method"
+	ERROR_UNSUPPORTED_OPERATION     = "unsupported operation"
+)
+
 type DriverError struct {
 	ErrorType  string `json:"Error"`
 	Message    string
@@ -13,3 +47,21 @@ func (e *DriverError) Error() string {
 func (e *DriverError) String() string {
 	return e.Error()
 }
+
+// IsDriverError returns true if err is a DriverError of one of the given
+// types, or of any type if none is given.
+func IsDriverError(err error, errorTypes ...string) bool {
+	var de *DriverError
+	if !errors.As(err, &de) {
+		return false
+	}
+	if len(errorTypes) == 0 {
+		return true
+	}
+	for _, t := range errorTypes {
+		if de.ErrorType == t {
+			return true
+		}
+	}
+	return false
+}
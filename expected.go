@@ -1,5 +1,13 @@
 package marionette
 
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"regexp"
+	"strings"
+)
+
 func ElementIsPresent(by By, value string) func(f Finder) (bool, *WebElement, error) {
 	return func(f Finder) (bool, *WebElement, error) {
 		result := true
@@ -23,3 +31,198 @@ func ElementIsNotPresent(by By, value string) func(f Finder) (bool, *WebElement,
 		return result, v, e
 	}
 }
+
+// TitleIs is met when the page title is title.
+func TitleIs(c *Client, title string) Condition[string] {
+	return func(ctx context.Context) (string, bool, error) {
+		v, err := c.Title()
+		return v, err == nil && v == title, err
+	}
+}
+
+// TitleContains is met when the page title contains substr.
+func TitleContains(c *Client, substr string) Condition[string] {
+	return func(ctx context.Context) (string, bool, error) {
+		v, err := c.Title()
+		return v, err == nil && strings.Contains(v, substr), err
+	}
+}
+
+// URLMatches is met when the current URL matches re.
+func URLMatches(c *Client, re *regexp.Regexp) Condition[string] {
+	return func(ctx context.Context) (string, bool, error) {
+		v, err := c.URL()
+		return v, err == nil && re.MatchString(v), err
+	}
+}
+
+// ElementVisible is met when the element is present and displayed.
+func ElementVisible(f Finder, by By, value string) Condition[*WebElement] {
+	return func(ctx context.Context) (*WebElement, bool, error) {
+		e, err := f.FindElement(by, value)
+		if err != nil {
+			return nil, false, err
+		}
+		return e, e.Displayed(), nil
+	}
+}
+
+// ElementClickable is met when the element is present, displayed and enabled.
+func ElementClickable(f Finder, by By, value string) Condition[*WebElement] {
+	return func(ctx context.Context) (*WebElement, bool, error) {
+		e, err := f.FindElement(by, value)
+		if err != nil {
+			return nil, false, err
+		}
+		return e, e.Displayed() && e.Enabled(), nil
+	}
+}
+
+// ElementHasText is met when the element is present and its text contains
+// text.
+func ElementHasText(f Finder, by By, value, text string) Condition[*WebElement] {
+	return func(ctx context.Context) (*WebElement, bool, error) {
+		e, err := f.FindElement(by, value)
+		if err != nil {
+			return nil, false, err
+		}
+		return e, strings.Contains(e.Text(), text), nil
+	}
+}
+
+// ElementStale is met when e is no longer attached to the document, as after
+// a navigation or a re-render. Lazy elements not located yet are not stale.
+func ElementStale(e *WebElement) Condition[bool] {
+	return func(ctx context.Context) (bool, bool, error) {
+		if e.id == "" {
+			return false, false, nil
+		}
+		_, err := e.c.tr.Send("WebDriver:GetElementTagName", map[string]any{"id": e.id})
+		if IsDriverError(err, ERROR_STALE_ELEMENT_REFERENCE) {
+			return true, true, nil
+		}
+		return false, false, err
+	}
+}
+
+// NumberOfElements is met when exactly n elements are found.
+func NumberOfElements(f Finder, by By, value string, n int) Condition[[]*WebElement] {
+	return func(ctx context.Context) ([]*WebElement, bool, error) {
+		es, err := f.FindElements(by, value)
+		return es, err == nil && len(es) == n, err
+	}
+}
+
+// AlertPresent is met when a user prompt is opened, and returns its text.
+func AlertPresent(c *Client) Condition[string] {
+	return func(ctx context.Context) (string, bool, error) {
+		text, err := c.TextFromAlert()
+		if IsDriverError(err, ERROR_NO_SUCH_ALERT) {
+			return "", false, nil
+		}
+		return text, err == nil, err
+	}
+}
+
+// NumberOfWindows is met when exactly n windows are opened, and returns their
+// handles.
+func NumberOfWindows(c *Client, n int) Condition[[]string] {
+	return func(ctx context.Context) ([]string, bool, error) {
+		handles, err := c.GetWindowHandles()
+		return handles, err == nil && len(handles) == n, err
+	}
+}
+
+// ScriptReturnsTruthy is met when script returns a truthy value in the
+// JavaScript sense, and returns that value.
+func ScriptReturnsTruthy(c *Client, script string, args ...any) Condition[any] {
+	return func(ctx context.Context) (any, bool, error) {
+		if args == nil {
+			args = []any{}
+		}
+		r, err := c.ExecuteScript(script, args, 0, false)
+		if err != nil {
+			return nil, false, err
+		}
+		var out struct {
+			Value any `json:"value"`
+		}
+		if err = json.Unmarshal([]byte(r.Value), &out); err != nil {
+			return nil, false, err
+		}
+		return out.Value, truthy(out.Value), nil
+	}
+}
+
+// truthy tells if a JSON decoded value is truthy in JavaScript.
+func truthy(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0 && !math.IsNaN(v)
+	case string:
+		return v != ""
+	default:
+		return true
+	}
+}
+
+// And is met when all the conditions are met, and returns the value of the
+// last one.
+func And[T any](conds ...Condition[T]) Condition[T] {
+	return func(ctx context.Context) (v T, ok bool, err error) {
+		for _, cond := range conds {
+			v, ok, err = cond(ctx)
+			if !ok || err != nil {
+				return
+			}
+		}
+		return
+	}
+}
+
+// Or is met when any of the conditions is met, and returns its value. The
+// conditions are evaluated in order; errors are returned only if no condition
+// is met.
+func Or[T any](conds ...Condition[T]) Condition[T] {
+	return func(ctx context.Context) (T, bool, error) {
+		var firstErr error
+		for _, cond := range conds {
+			v, ok, err := cond(ctx)
+			if ok {
+				return v, true, nil
+			}
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		var zero T
+		return zero, false, firstErr
+	}
+}
+
+// Not is met when cond is not. Missing and stale elements are considered not
+// meeting cond, so Not(ElementVisible(...)) is met once the element is gone.
+func Not[T any](cond Condition[T]) Condition[T] {
+	return func(ctx context.Context) (T, bool, error) {
+		v, ok, err := cond(ctx)
+		if IsDriverError(err, ERROR_NO_SUCH_ELEMENT, ERROR_STALE_ELEMENT_REFERENCE) {
+			return v, true, nil
+		}
+		if err != nil {
+			return v, false, err
+		}
+		return v, !ok, nil
+	}
+}
+
+// AsAny converts cond to a Condition[any], to combine conditions of different
+// value types.
+func AsAny[T any](cond Condition[T]) Condition[any] {
+	return func(ctx context.Context) (any, bool, error) {
+		return cond(ctx)
+	}
+}
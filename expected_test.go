@@ -1,7 +1,9 @@
 package marionette
 
 import (
+	"context"
 	"errors"
+	"math"
 	"testing"
 	"time"
 )
@@ -29,6 +31,10 @@ func (f fakeFinder) FindElements(by By, value string) ([]*WebElement, error) {
 
 func TestExpected(t *testing.T) {
 	t.Run("ElementIsPresentFalseTest", ElementIsPresentFalseTest)
+	t.Run("CombinatorsTest", CombinatorsTest)
+	t.Run("TruthyTest", TruthyTest)
+	t.Run("WaitForTest", WaitForTest)
+	t.Run("ElementStaleLazyTest", ElementStaleLazyTest)
 }
 
 func ElementIsPresentFalseTest(t *testing.T) {
@@ -53,3 +59,90 @@ func NotPresentTest(t *testing.T) {
 		t.Fatal("Element Was Found in ElementIsNotPresent test.")
 	}
 }
+
+func ElementStaleLazyTest(t *testing.T) {
+	lazy := &WebElement{c: client, loc: &elementLocator{
+		desc:    `id "lazy"`,
+		resolve: func() (*WebElement, error) { return nil, errors.New("not located") },
+	}}
+
+	// nothing is sent to the unconnected client
+	stale, ok, err := ElementStale(lazy)(context.Background())
+	if stale || ok || err != nil {
+		t.Fatalf("expected a lazy element not to be stale, got %v %v %v", stale, ok, err)
+	}
+}
+
+func constCondition[T any](v T, ok bool, err error) Condition[T] {
+	return func(ctx context.Context) (T, bool, error) {
+		return v, ok, err
+	}
+}
+
+func CombinatorsTest(t *testing.T) {
+	ctx := context.Background()
+	someErr := errors.New("some error")
+	notFound := &DriverError{ErrorType: ERROR_NO_SUCH_ELEMENT}
+
+	for _, tc := range []struct {
+		name  string
+		cond  Condition[int]
+		value int
+		ok    bool
+		err   error
+	}{
+		{"and", And(constCondition(1, true, nil), constCondition(2, true, nil)), 2, true, nil},
+		{"and not met", And(constCondition(1, false, nil), constCondition(2, true, nil)), 1, false, nil},
+		{"and error", And(constCondition(1, true, nil), constCondition(2, false, someErr)), 2, false, someErr},
+		{"or", Or(constCondition(1, false, someErr), constCondition(2, true, nil)), 2, true, nil},
+		{"or not met", Or(constCondition(1, false, someErr), constCondition(2, false, nil)), 0, false, someErr},
+		{"not", Not(constCondition(1, false, nil)), 1, true, nil},
+		{"not met", Not(constCondition(1, true, nil)), 1, false, nil},
+		{"not missing", Not(constCondition(0, false, error(notFound))), 0, true, nil},
+		{"not error", Not(constCondition(0, false, someErr)), 0, false, someErr},
+	} {
+		v, ok, err := tc.cond(ctx)
+		if v != tc.value || ok != tc.ok || err != tc.err {
+			t.Fatalf("%s: expected (%v, %v, %v), got (%v, %v, %v)", tc.name, tc.value, tc.ok, tc.err, v, ok, err)
+		}
+	}
+
+	v, ok, _ := Or(AsAny(constCondition("a", false, nil)), AsAny(constCondition(1, true, nil)))(ctx)
+	if v != 1 || !ok {
+		t.Fatalf("unexpected AsAny result: %v %v", v, ok)
+	}
+}
+
+func TruthyTest(t *testing.T) {
+	for _, v := range []any{nil, false, 0.0, math.NaN(), ""} {
+		if truthy(v) {
+			t.Fatalf("%#v should be falsy", v)
+		}
+	}
+	for _, v := range []any{true, 1.0, "0", []any{}, map[string]any{}} {
+		if !truthy(v) {
+			t.Fatalf("%#v should be truthy", v)
+		}
+	}
+}
+
+func WaitForTest(t *testing.T) {
+	n := 0
+	counter := Condition[int](func(ctx context.Context) (int, bool, error) {
+		n++
+		return n, n == 3, nil
+	})
+
+	v, err := WaitFor(Wait(client).For(time.Second).Poll(time.Millisecond), counter)
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if v != 3 {
+		t.Fatalf("expected 3, got %v", v)
+	}
+
+	_, err = WaitFor(Wait(client).For(10*time.Millisecond).Poll(time.Millisecond), Not(constCondition(1, true, nil)))
+	if _, ok := err.(*TimeoutError); !ok {
+		t.Fatalf("expected a timeout, got %#v", err)
+	}
+}
@@ -0,0 +1,269 @@
+package marionette
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// extractField is the description of a struct field sent to extractScript.
+type extractField struct {
+	Name   string          `json:"name"`
+	CSS    string          `json:"css,omitempty"`
+	Attr   string          `json:"attr,omitempty"`
+	Multi  bool            `json:"multi,omitempty"`
+	Fields []*extractField `json:"fields,omitempty"`
+
+	index  int
+	layout string
+	typ    reflect.Type
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Extract fills the struct pointed by dest with data from the page, in a
+// single script execution. root is a Client, or a WebElement to extract from.
+//
+// Fields are tagged with a CSS selector and the attribute to read:
+//
+//	type Article struct {
+//		Title  string    `css:"h2"`
+//		Link   string    `css:"a" attr:"href"`
+//		Date   time.Time `css:"time" attr:"datetime" layout:"2006-01-02"`
+//		Votes  int       `css:".votes"`
+//		Tags   []string  `css:".tag"`
+//	}
+//	var articles struct {
+//		List []Article `css:"article"`
+//	}
+//	err := Extract(client, &articles)
+//
+// attr is "text" (the default), "html", "outer-html", "value", "prop:name"
+// to read a DOM property, or the name of an attribute. Without css, the
+// value is read from the root element.
+//
+// Fields can be strings, numbers, booleans, time.Time (parsed with the
+// layout tag, RFC 3339 by default), pointers to them (left nil when no
+// element matches), structs (extracted from the first element matching css,
+// or from the root element) and slices of those (one item per element).
+// Fields of other types and untagged fields are left untouched. Recursive
+// struct types can't be extracted.
+//
+// All the fields are processed, then a PageError lists the failed ones.
+func Extract(root Finder, dest any) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("dest must be a pointer to a struct, not %T", dest)
+	}
+
+	fields, err := extractFields(v.Elem().Type())
+	if err != nil {
+		return err
+	}
+
+	r, err := executeScriptFrom(root, extractScript, fields)
+	if err != nil {
+		return err
+	}
+	var out struct {
+		Value map[string]any `json:"value"`
+	}
+	if err = json.Unmarshal([]byte(r.Value), &out); err != nil {
+		return err
+	}
+
+	var errs []*PageFieldError
+	decodeExtracted(v.Elem(), fields, out.Value, "", &errs)
+	if len(errs) != 0 {
+		return &PageError{Fields: errs}
+	}
+	return nil
+}
+
+// extractFields describes the fields of the struct type t.
+func extractFields(t reflect.Type) ([]*extractField, error) {
+	return extractStructFields(t, map[reflect.Type]bool{})
+}
+
+// extractStructFields describes the fields of the struct type t, expanding
+// being the struct types t is nested in.
+func extractStructFields(t reflect.Type, expanding map[reflect.Type]bool) (fields []*extractField, err error) {
+	if expanding[t] {
+		return nil, fmt.Errorf("can't extract the recursive type %v", t)
+	}
+	expanding[t] = true
+	defer delete(expanding, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		css, hasCSS := sf.Tag.Lookup("css")
+		attr, hasAttr := sf.Tag.Lookup("attr")
+
+		f := &extractField{
+			Name:   sf.Name,
+			CSS:    css,
+			Attr:   attr,
+			index:  i,
+			layout: sf.Tag.Get("layout"),
+			typ:    sf.Type,
+		}
+
+		typ := sf.Type
+		if typ.Kind() == reflect.Slice {
+			f.Multi = true
+			typ = typ.Elem()
+		}
+		if typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+
+		if typ.Kind() == reflect.Struct && typ != timeType {
+			if f.Fields, err = extractStructFields(typ, expanding); err != nil {
+				return nil, err
+			}
+		} else if !hasCSS && !hasAttr {
+			continue
+		}
+		if f.Multi && !hasCSS {
+			continue // a list needs a selector
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+func decodeExtracted(v reflect.Value, fields []*extractField, values map[string]any, prefix string, errs *[]*PageFieldError) {
+	for _, f := range fields {
+		name := prefix + f.Name
+		fv := v.Field(f.index)
+		raw := values[f.Name]
+
+		if !f.Multi {
+			decodeExtractedValue(fv, f, raw, name, errs)
+			continue
+		}
+
+		items, _ := raw.([]any)
+		slice := reflect.MakeSlice(f.typ, len(items), len(items))
+		for i, item := range items {
+			decodeExtractedValue(slice.Index(i), f, item, fmt.Sprintf("%s[%d]", name, i), errs)
+		}
+		fv.Set(slice)
+	}
+}
+
+func decodeExtractedValue(v reflect.Value, f *extractField, raw any, name string, errs *[]*PageFieldError) {
+	if raw == nil {
+		return
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	if f.Fields != nil {
+		values, _ := raw.(map[string]any)
+		decodeExtracted(v, f.Fields, values, name+".", errs)
+		return
+	}
+
+	s, _ := raw.(string)
+	if err := setString(v, s, f.layout); err != nil {
+		tag := "css=" + f.CSS
+		if f.Attr != "" {
+			tag += " attr=" + f.Attr
+		}
+		*errs = append(*errs, &PageFieldError{Field: name, Tag: tag, Err: err})
+	}
+}
+
+// setString sets v from the string s, converted to v's type.
+func setString(v reflect.Value, s, layout string) error {
+	if v.Type() == timeType {
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		t, err := time.Parse(layout, strings.TrimSpace(s))
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(strings.TrimSpace(s), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(strings.TrimSpace(s), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %v", v.Type())
+	}
+	return nil
+}
+
+// extractScript evaluates extractField descriptions from the root element.
+const extractScript = `
+let [root, fields] = arguments;
+root = root || document.documentElement;
+
+let read = (el, attr) => {
+	let v;
+	if (attr == "text") {
+		v = (el.innerText ?? el.textContent).trim();
+	} else if (attr == "html") {
+		v = el.innerHTML;
+	} else if (attr == "outer-html") {
+		v = el.outerHTML;
+	} else if (attr == "value") {
+		v = el.value;
+	} else if (attr.startsWith("prop:")) {
+		v = el[attr.slice(5)];
+	} else {
+		v = el.getAttribute(attr);
+	}
+	return v == null ? null : String(v);
+};
+
+let extract = (root, fields) => {
+	let out = {};
+	for (let f of fields) {
+		let els = f.css ? Array.from(root.querySelectorAll(f.css)) : [root];
+		let conv = (el) => f.fields ? extract(el, f.fields) : read(el, f.attr || "text");
+		out[f.name] = f.multi ? els.map(conv) : (els.length ? conv(els[0]) : null);
+	}
+	return out;
+};
+
+return extract(root, fields);
+`
@@ -0,0 +1,146 @@
+package marionette
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type extractedRow struct {
+	ID   int     `css:"td:nth-child(1)"`
+	Name string  `css:"td:nth-child(2)"`
+	Key  *string `css:"td[id]" attr:"id"`
+}
+
+type extractedPage struct {
+	Title string `css:"title"`
+	Table struct {
+		Width float64        `attr:"width"`
+		Rows  []extractedRow `css:"tbody tr"`
+	} `css:"#the-table"`
+	Date    time.Time `css:"time" attr:"datetime" layout:"2006-01-02"`
+	Headers []string  `css:"thead td"`
+
+	Ignored string
+	private string `css:"p"`
+}
+
+func TestExtract(t *testing.T) {
+	t.Run("ExtractFieldsTest", ExtractFieldsTest)
+	t.Run("DecodeExtractedTest", DecodeExtractedTest)
+	t.Run("ExtractRecursiveTest", ExtractRecursiveTest)
+}
+
+type extractedComment struct {
+	Text    string             `css:".text"`
+	Replies []extractedComment `css:".reply"`
+}
+
+func ExtractRecursiveTest(t *testing.T) {
+	if _, err := extractFields(reflect.TypeOf(extractedComment{})); err == nil {
+		t.Fatal("expected an error on a recursive type")
+	}
+	var page struct {
+		Comment *struct {
+			Parent *extractedComment `css:".parent"`
+		} `css:".comment"`
+	}
+	if err := Extract(nil, &page); err == nil {
+		t.Fatal("expected an error on a nested recursive type")
+	}
+
+	// the same type twice is not recursive
+	var twice struct {
+		A extractedRow `css:"#a"`
+		B extractedRow `css:"#b"`
+	}
+	if _, err := extractFields(reflect.TypeOf(twice)); err != nil {
+		t.Fatalf("%#v", err)
+	}
+}
+
+func ExtractFieldsTest(t *testing.T) {
+	fields, err := extractFields(reflect.TypeOf(extractedPage{}))
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	expected := `[{"name":"Title","css":"title"},` +
+		`{"name":"Table","css":"#the-table","fields":[{"name":"Width","attr":"width"},{"name":"Rows","css":"tbody tr","multi":true,"fields":[` +
+		`{"name":"ID","css":"td:nth-child(1)"},{"name":"Name","css":"td:nth-child(2)"},{"name":"Key","css":"td[id]","attr":"id"}]}]},` +
+		`{"name":"Date","css":"time","attr":"datetime"},` +
+		`{"name":"Headers","css":"thead td","multi":true}]`
+	if string(b) != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, b)
+	}
+}
+
+func DecodeExtractedTest(t *testing.T) {
+	var values map[string]any
+	err := json.Unmarshal([]byte(`{
+		"Title": "Table Test page",
+		"Table": {"Width": "50", "Rows": [
+			{"ID": "1", "Name": "John Doe", "Key": "Administrator"},
+			{"ID": "two", "Name": "Alice", "Key": null}
+		]},
+		"Date": "2023-03-14",
+		"Headers": ["ID", "Name"]
+	}`), &values)
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	var page extractedPage
+	var errs []*PageFieldError
+	fields, err := extractFields(reflect.TypeOf(page))
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	decodeExtracted(reflect.ValueOf(&page).Elem(), fields, values, "", &errs)
+
+	if len(errs) != 1 || errs[0].Field != "Table.Rows[1].ID" {
+		t.Fatalf("expected an error on Table.Rows[1].ID, got %v", &PageError{Fields: errs})
+	}
+
+	if page.Title != "Table Test page" || page.Table.Width != 50 || len(page.Table.Rows) != 2 {
+		t.Fatalf("unexpected page %#v", page)
+	}
+	row := page.Table.Rows[0]
+	if row.ID != 1 || row.Name != "John Doe" || row.Key == nil || *row.Key != "Administrator" {
+		t.Fatalf("unexpected row %#v", row)
+	}
+	if page.Table.Rows[1].Key != nil {
+		t.Fatal("missing value should leave a nil pointer")
+	}
+	if !page.Date.Equal(time.Date(2023, 3, 14, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected date %v", page.Date)
+	}
+	if !reflect.DeepEqual(page.Headers, []string{"ID", "Name"}) {
+		t.Fatalf("unexpected headers %v", page.Headers)
+	}
+}
+
+// required test in sequential main client test: client_test.go
+func ExtractTest(t *testing.T) {
+	navigateLocal("table.html")
+
+	var page extractedPage
+	err := Extract(client, &page)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if page.Title != "Table Test page" {
+		t.Fatalf("unexpected title %q", page.Title)
+	}
+	if len(page.Table.Rows) != 2 || page.Table.Rows[1].Name != "Alice" {
+		t.Fatalf("unexpected rows %#v", page.Table.Rows)
+	}
+	if page.Table.Rows[0].Key == nil || *page.Table.Rows[0].Key != "Administrator" {
+		t.Fatalf("unexpected key %#v", page.Table.Rows[0].Key)
+	}
+}
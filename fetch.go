@@ -0,0 +1,250 @@
+package marionette
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// fetchScriptMargin is added to the deadline of fetches to get the script
+// timeout, so that the script aborts the request first.
+const fetchScriptMargin = time.Second
+
+// fetchScript sends a request with the browser's fetch, with the cookies of
+// the context, and resolves with the response, bodies being encoded in
+// base64.
+const fetchScript = `
+let [req, timeout] = arguments;
+let resolve = arguments[arguments.length - 1];
+let ctrl = new AbortController();
+if (timeout > 0) {
+	setTimeout(() => ctrl.abort(), timeout);
+}
+let init = {method: req.method, headers: req.headers, credentials: "include", signal: ctrl.signal};
+if (req.body !== null) {
+	init.body = Uint8Array.from(atob(req.body), (c) => c.charCodeAt(0));
+}
+fetch(req.url, init).then(async (r) => {
+	let buf = new Uint8Array(await r.arrayBuffer());
+	let bin = "";
+	for (let i = 0; i < buf.length; i += 0x8000) {
+		bin += String.fromCharCode.apply(null, buf.subarray(i, i + 0x8000));
+	}
+	resolve({
+		status: r.status,
+		statusText: r.statusText,
+		url: r.url,
+		headers: Array.from(r.headers.entries()),
+		body: btoa(bin),
+	});
+}).catch(async (e) => {
+	// a cross-origin server reachable without CORS means the response was
+	// blocked, a HEAD request having no side effects
+	let blocked = false;
+	if (!ctrl.signal.aborted && new URL(req.url, location.href).origin != location.origin) {
+		try {
+			await fetch(req.url, {method: "HEAD", mode: "no-cors", credentials: "include", signal: ctrl.signal});
+			blocked = true;
+		} catch (e) {}
+	}
+	resolve({error: String(e), blocked});
+});
+`
+
+// FetchError is returned when the browser could not send a request, as on
+// network errors or requests blocked by CORS.
+type FetchError struct {
+	URL     string
+	Message string
+	// Blocked is true when the server was reached but its response was
+	// blocked, as with cross-origin requests without CORS.
+	Blocked bool
+}
+
+func (e *FetchError) Error() string {
+	return fmt.Sprintf("fetch of %s failed: %s", e.URL, e.Message)
+}
+
+// fetchRequest is a request as sent to fetchScript.
+type fetchRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers [][2]string `json:"headers"`
+	Body    *string     `json:"body"`
+}
+
+// fetchResponse is a response as returned by fetchScript.
+type fetchResponse struct {
+	Status     int         `json:"status"`
+	StatusText string      `json:"statusText"`
+	URL        string      `json:"url"`
+	Headers    [][2]string `json:"headers"`
+	Body       string      `json:"body"`
+	Error      string      `json:"error"`
+	Blocked    bool        `json:"blocked"`
+}
+
+func newFetchRequest(req *http.Request) (*fetchRequest, error) {
+	fr := &fetchRequest{Method: req.Method, URL: req.URL.String(), Headers: [][2]string{}}
+	if fr.Method == "" {
+		fr.Method = http.MethodGet
+	}
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, v := range req.Header[name] {
+			fr.Headers = append(fr.Headers, [2]string{name, v})
+		}
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body := base64.StdEncoding.EncodeToString(data)
+		fr.Body = &body
+	}
+	return fr, nil
+}
+
+// httpResponse converts r to an http.Response to req. As with net/http, the
+// body is already decompressed and the Content-Encoding and Content-Length
+// headers are removed in this case.
+func (r *fetchResponse) httpResponse(req *http.Request) (*http.Response, error) {
+	body, err := base64.StdEncoding.DecodeString(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	header := http.Header{}
+	for _, h := range r.Headers {
+		header.Add(h[0], h[1])
+	}
+	uncompressed := false
+	if header.Get("Content-Encoding") != "" {
+		header.Del("Content-Encoding")
+		header.Del("Content-Length")
+		uncompressed = true
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, r.StatusText),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Uncompressed:  uncompressed,
+		Request:       req,
+	}, nil
+}
+
+// Fetch sends req from the current page with the browser's fetch, using its
+// cookies, authentication and proxy, and returns the response with its
+// whole body. Relative URLs are resolved against the page URL.
+//
+// The browser applies its rules: cross-origin requests need CORS, and
+// headers like Cookie or Host can't be set. Responses are returned whatever
+// their status, errors being returned when no response was received, as a
+// FetchError from the browser. The deadline of ctx bounds the request,
+// instead of the session script timeout; without deadline, the session
+// script timeout applies.
+func (c *Client) Fetch(ctx context.Context, req *http.Request) (*http.Response, error) {
+	fr, err := newFetchRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	r, err := c.fetch(ctx, fr)
+	if err != nil {
+		return nil, err
+	}
+	return r.httpResponse(req)
+}
+
+// FetchInChrome is Fetch from the chrome context, which is not restricted by
+// CORS. The previous context is restored afterwards.
+//
+// req must have an absolute URL: relative ones would be resolved against
+// the chrome:// document of the browser window, not the current page.
+func (c *Client) FetchInChrome(ctx context.Context, req *http.Request) (resp *http.Response, err error) {
+	if !req.URL.IsAbs() {
+		return nil, fmt.Errorf("fetch from the chrome context needs an absolute URL, not %q", req.URL)
+	}
+
+	r, err := c.Context()
+	if err != nil {
+		return nil, err
+	}
+	var out struct {
+		Value string `json:"value"`
+	}
+	if err = json.Unmarshal([]byte(r.Value), &out); err != nil {
+		return nil, err
+	}
+
+	if out.Value != CHROME.String() {
+		if _, err = c.SetContext(CHROME); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if _, serr := c.SetContext(CONTENT); serr != nil && err == nil {
+				resp, err = nil, serr
+			}
+		}()
+	}
+	return c.Fetch(ctx, req)
+}
+
+func (c *Client) fetch(ctx context.Context, req *fetchRequest) (*fetchResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var (
+		r   *Response
+		err error
+	)
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout := time.Until(deadline)
+		if timeout <= 0 {
+			return nil, context.DeadlineExceeded
+		}
+		r, err = c.ExecuteAsyncScriptTimeout(fetchScript, []any{req, timeout.Milliseconds()},
+			timeout+fetchScriptMargin, false)
+	} else {
+		r, err = c.ExecuteAsyncScript(fetchScript, []any{req, 0}, false)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	var out struct {
+		Value fetchResponse `json:"value"`
+	}
+	if err = json.Unmarshal([]byte(r.Value), &out); err != nil {
+		return nil, err
+	}
+	if out.Value.Error != "" {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, &FetchError{URL: req.URL, Message: out.Value.Error, Blocked: out.Value.Blocked}
+	}
+	return &out.Value, nil
+}
@@ -0,0 +1,201 @@
+package marionette
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetch(t *testing.T) {
+	t.Run("FetchRequestTest", FetchRequestTest)
+	t.Run("FetchResponseTest", FetchResponseTest)
+	t.Run("FetchInChromeRelativeTest", FetchInChromeRelativeTest)
+}
+
+func FetchInChromeRelativeTest(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api", nil)
+	if _, err := client.FetchInChrome(context.Background(), req); err == nil {
+		t.Fatal("expected an error for a relative URL")
+	}
+}
+
+func FetchRequestTest(t *testing.T) {
+	req, _ := http.NewRequest("", "http://example.com/api?q=1", nil)
+	req.Header.Add("X-B", "2")
+	req.Header.Add("X-A", "1")
+	req.Header.Add("X-A", "3")
+
+	fr, err := newFetchRequest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fr.Method != "GET" || fr.URL != "http://example.com/api?q=1" || fr.Body != nil {
+		t.Fatalf("unexpected request %+v", fr)
+	}
+	expected := [][2]string{{"X-A", "1"}, {"X-A", "3"}, {"X-B", "2"}}
+	if len(fr.Headers) != len(expected) {
+		t.Fatalf("unexpected headers %v", fr.Headers)
+	}
+	for i := range expected {
+		if fr.Headers[i] != expected[i] {
+			t.Fatalf("unexpected headers %v", fr.Headers)
+		}
+	}
+
+	req, _ = http.NewRequest("POST", "/api", strings.NewReader("hello"))
+	if fr, err = newFetchRequest(req); err != nil {
+		t.Fatal(err)
+	}
+	if fr.Body == nil || *fr.Body != base64.StdEncoding.EncodeToString([]byte("hello")) {
+		t.Fatalf("unexpected body %v", fr.Body)
+	}
+}
+
+func FetchResponseTest(t *testing.T) {
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+	fr := &fetchResponse{
+		Status:     404,
+		StatusText: "Not Found",
+		Headers: [][2]string{
+			{"content-type", "text/plain"},
+			{"content-encoding", "gzip"},
+			{"content-length", "20"},
+			{"set-cookie", "a=1"},
+		},
+		Body: base64.StdEncoding.EncodeToString([]byte("not found")),
+	}
+
+	resp, err := fr.httpResponse(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 404 || resp.Status != "404 Not Found" || resp.Request != req {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	if resp.Header.Get("Content-Type") != "text/plain" || resp.Header.Get("Set-Cookie") != "a=1" {
+		t.Fatalf("unexpected headers %v", resp.Header)
+	}
+	if !resp.Uncompressed || resp.Header.Get("Content-Encoding") != "" || resp.Header.Get("Content-Length") != "" {
+		t.Fatalf("expected an uncompressed response: %v", resp.Header)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "not found" || resp.ContentLength != 9 {
+		t.Fatalf("unexpected body %q", body)
+	}
+}
+
+// echoServer sets a session cookie on /, echoes requests on /echo and
+// answers after a second on /slow.
+func echoServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "s3cr3t", Path: "/"})
+		w.Header().Set("Content-Type", "text/html")
+		io.WriteString(w, "<html><body>logged in</body></html>")
+	})
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		session, _ := r.Cookie("session")
+		echo := map[string]string{
+			"method": r.Method,
+			"header": r.Header.Get("X-Test"),
+			"body":   string(body),
+		}
+		if session != nil {
+			echo["session"] = session.Value
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Echo", "yes")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(echo)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+		io.WriteString(w, "done")
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// required test in sequential main client test: client_test.go
+func FetchTest(t *testing.T) {
+	srv := echoServer(t)
+	if _, err := client.Navigate(srv.URL + "/"); err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, fetch := range []func(context.Context, *http.Request) (*http.Response, error){client.Fetch, client.FetchInChrome} {
+		req, _ := http.NewRequest("POST", srv.URL+"/echo", strings.NewReader("payload"))
+		req.Header.Set("X-Test", "header")
+
+		resp, err := fetch(ctx, req)
+		if err != nil {
+			t.Fatalf("%#v", err)
+		}
+		if resp.StatusCode != http.StatusCreated || resp.Header.Get("X-Echo") != "yes" {
+			t.Fatalf("unexpected response %s %v", resp.Status, resp.Header)
+		}
+
+		var echo map[string]string
+		if err = json.NewDecoder(resp.Body).Decode(&echo); err != nil {
+			t.Fatal(err)
+		}
+		if echo["method"] != "POST" || echo["header"] != "header" || echo["body"] != "payload" || echo["session"] != "s3cr3t" {
+			t.Fatalf("unexpected echo %v", echo)
+		}
+	}
+
+	// relative to the page
+	req, _ := http.NewRequest("GET", "/echo", nil)
+	resp, err := client.Fetch(ctx, req)
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("unexpected status %s", resp.Status)
+	}
+}
+
+// required test in sequential main client test: client_test.go
+func FetchTimeoutTest(t *testing.T) {
+	srv := echoServer(t)
+	if _, err := client.Navigate(srv.URL + "/"); err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	timeouts, err := client.GetTimeouts()
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	defer client.SetScriptTimeout(time.Duration(timeouts["script"]) * time.Millisecond)
+	if _, err = client.SetScriptTimeout(200 * time.Millisecond); err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	// the deadline replaces the session script timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, _ := http.NewRequest("GET", srv.URL+"/slow", nil)
+	if _, err = client.Fetch(ctx, req); err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if _, err = client.Fetch(ctx, req); err != context.DeadlineExceeded {
+		t.Fatalf("expected the context error, got %v", err)
+	}
+}
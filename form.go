@@ -0,0 +1,279 @@
+package marionette
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// formTimeLayouts are the value formats of date and time inputs.
+var formTimeLayouts = map[string]string{
+	"date":           "2006-01-02",
+	"time":           "15:04:05",
+	"datetime-local": "2006-01-02T15:04:05",
+	"month":          "2006-01",
+}
+
+// setValueScript sets the value of inputs that can't be typed reliably, like
+// dates, and fires the events a user input would.
+const setValueScript = `
+let [el, value] = arguments;
+el.value = value;
+el.dispatchEvent(new Event("input", {bubbles: true}));
+el.dispatchEvent(new Event("change", {bubbles: true}));
+`
+
+// submitScript submits a form as if its default button was clicked.
+const submitScript = `
+let form = arguments[0];
+form.requestSubmit ? form.requestSubmit() : form.submit();
+`
+
+// formField is a value to fill in a form.
+type formField struct {
+	key   string
+	value any
+}
+
+// formFields lists the values of a map with string keys, or of a struct
+// whose fields are named by their "form" tag or their name.
+func formFields(values any) ([]formField, error) {
+	v := reflect.ValueOf(values)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+
+	var fields []formField
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("form values keys must be strings, not %v", v.Type().Key())
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			fields = append(fields, formField{iter.Key().String(), iter.Value().Interface()})
+		}
+		sort.Slice(fields, func(i, j int) bool { return fields[i].key < fields[j].key })
+
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			key, ok := sf.Tag.Lookup("form")
+			if key == "-" {
+				continue
+			}
+			if !ok {
+				key = sf.Name
+			}
+			fields = append(fields, formField{key, v.Field(i).Interface()})
+		}
+
+	default:
+		return nil, fmt.Errorf("form values must be a map or a struct, not %T", values)
+	}
+	return fields, nil
+}
+
+// FillForm fills the controls of form with values, a map with string keys or
+// a struct whose fields are named by their "form" tag or their name. Keys
+// match controls by name, id, or label text, in this order.
+//
+// Text inputs and textareas are cleared and typed in, checkboxes are checked
+// or unchecked according to a bool, radios are chosen by value, selects by
+// value or visible text ([]string for multiple selects), date and time
+// inputs accept time.Time values and file inputs take one or more paths.
+//
+// If submit is true, the form is submitted once filled. The keys that matched
+// no control are returned, and a PageError lists the ones that failed.
+func FillForm(form *WebElement, values any, submit bool) (unmatched []string, err error) {
+	fields, err := formFields(values)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []*PageFieldError
+	for _, f := range fields {
+		controls, err := findFormControls(form, f.key)
+		if err != nil {
+			errs = append(errs, &PageFieldError{Field: f.key, Tag: "form", Err: err})
+			continue
+		}
+		if len(controls) == 0 {
+			unmatched = append(unmatched, f.key)
+			continue
+		}
+		if err = fillFormControls(controls, f.value); err != nil {
+			errs = append(errs, &PageFieldError{Field: f.key, Tag: "form", Err: err})
+		}
+	}
+	if len(errs) != 0 {
+		return unmatched, &PageError{Fields: errs}
+	}
+
+	if submit {
+		_, err = executeScriptFrom(form, submitScript)
+	}
+	return unmatched, err
+}
+
+func findFormControls(form *WebElement, key string) ([]*WebElement, error) {
+	controls, err := form.FindElements(NAME, key)
+	if err != nil || len(controls) != 0 {
+		return controls, err
+	}
+	controls, err = form.FindElements(CSS_SELECTOR, "#"+cssEscape(key))
+	if err != nil || len(controls) != 0 {
+		return controls, err
+	}
+	return Label(Exactly(key)).FindAll(form)
+}
+
+// cssEscape escapes s to be used as a CSS identifier.
+func cssEscape(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', r == '-', r > 0x7f,
+			r >= '0' && r <= '9' && i != 0:
+			b.WriteRune(r)
+		default:
+			fmt.Fprintf(&b, "\\%x ", r)
+		}
+	}
+	return b.String()
+}
+
+func fillFormControls(controls []*WebElement, value any) error {
+	e := controls[0]
+	tag, err := Property[string](e, "localName")
+	if err != nil {
+		return err
+	}
+	typ, err := Property[string](e, "type")
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case tag == "select":
+		return fillSelect(e, value)
+
+	case tag == "input" && typ == "checkbox":
+		if len(controls) > 1 {
+			return fillChoices(controls, value, false)
+		}
+		checked, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("checkbox value must be a bool, not %T", value)
+		}
+		if e.Selected() != checked {
+			return e.Click()
+		}
+		return nil
+
+	case tag == "input" && typ == "radio":
+		return fillChoices(controls, value, true)
+
+	case tag == "input" && typ == "file":
+		return UploadFiles(e, formValues(value)...)
+
+	case tag == "input" && (formTimeLayouts[typ] != "" || typ == "color" || typ == "range"):
+		s := formatFormValue(value, formTimeLayouts[typ])
+		_, err = executeScriptFrom(e, setValueScript, s)
+		return err
+
+	case tag == "input" && typ == "hidden":
+		return fmt.Errorf("can't fill a hidden input")
+
+	case tag == "input", tag == "textarea":
+		if err = e.Clear(); err != nil {
+			return err
+		}
+		return e.SendKeys(formatFormValue(value, ""))
+	}
+	return fmt.Errorf("can't fill a <%s>", tag)
+}
+
+// fillChoices checks the radios or checkboxes whose value is in value, a
+// string or a []string. Other checkboxes are unchecked, radios being
+// unchecked by the browser.
+func fillChoices(controls []*WebElement, value any, radio bool) error {
+	wanted := map[string]bool{}
+	for _, v := range formValues(value) {
+		wanted[v] = true
+	}
+	found := 0
+	for _, c := range controls {
+		v, err := Property[string](c, "value")
+		if err != nil {
+			return err
+		}
+		if wanted[v] {
+			found++
+		}
+		if radio && !wanted[v] {
+			continue
+		}
+		if c.Selected() != wanted[v] {
+			if err = c.Click(); err != nil {
+				return err
+			}
+		}
+	}
+	if found != len(wanted) {
+		return fmt.Errorf("no choice for some of the values %v", formValues(value))
+	}
+	return nil
+}
+
+func fillSelect(e *WebElement, value any) error {
+	s, err := NewSelect(e)
+	if err != nil {
+		return err
+	}
+	if s.Multiple() {
+		if err = s.DeselectAll(); err != nil {
+			return err
+		}
+	}
+	for _, v := range formValues(value) {
+		err = s.SelectByValue(v)
+		if IsDriverError(err, ERROR_NO_SUCH_ELEMENT) {
+			err = s.SelectByText(v)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formValues returns value as a list of strings.
+func formValues(value any) []string {
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		values := make([]string, v.Len())
+		for i := range values {
+			values[i] = formatFormValue(v.Index(i).Interface(), "")
+		}
+		return values
+	}
+	return []string{formatFormValue(value, "")}
+}
+
+// formatFormValue formats value for an input, using layout for time values.
+func formatFormValue(value any, layout string) string {
+	if t, ok := value.(time.Time); ok {
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		return t.Format(layout)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,126 @@
+package marionette
+
+import (
+	"testing"
+	"time"
+)
+
+func TestForm(t *testing.T) {
+	t.Run("FormFieldsTest", FormFieldsTest)
+	t.Run("FormValuesTest", FormValuesTest)
+}
+
+func FormFieldsTest(t *testing.T) {
+	fields, err := formFields(map[string]any{"b": 1, "a": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fields) != 2 || fields[0].key != "a" || fields[1].key != "b" {
+		t.Fatalf("unexpected fields %+v", fields)
+	}
+
+	type login struct {
+		Email    string `form:"email"`
+		Remember bool
+		Ignored  string `form:"-"`
+		private  string
+	}
+	fields, err = formFields(&login{Email: "me@example.com", Remember: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fields) != 2 || fields[0].key != "email" || fields[0].value != "me@example.com" ||
+		fields[1].key != "Remember" || fields[1].value != true {
+		t.Fatalf("unexpected fields %+v", fields)
+	}
+
+	if _, err = formFields(map[int]string{}); err == nil {
+		t.Fatal("expected an error for non-string keys")
+	}
+	if _, err = formFields("value"); err == nil {
+		t.Fatal("expected an error for a string")
+	}
+}
+
+func FormValuesTest(t *testing.T) {
+	if v := formValues([]string{"ham", "eggs"}); len(v) != 2 || v[0] != "ham" || v[1] != "eggs" {
+		t.Fatalf("unexpected values %v", v)
+	}
+	if v := formValues(42); len(v) != 1 || v[0] != "42" {
+		t.Fatalf("unexpected values %v", v)
+	}
+
+	d := time.Date(2023, 3, 14, 15, 9, 26, 0, time.UTC)
+	if s := formatFormValue(d, formTimeLayouts["date"]); s != "2023-03-14" {
+		t.Fatalf("unexpected date %q", s)
+	}
+	if s := formatFormValue(d, formTimeLayouts["datetime-local"]); s != "2023-03-14T15:09:26" {
+		t.Fatalf("unexpected datetime %q", s)
+	}
+
+	if s := cssEscape("1st-field.name"); s != `\31 st-field\2e name` {
+		t.Fatalf("unexpected escape %q", s)
+	}
+}
+
+func findElement(t *testing.T, by By, value string) *WebElement {
+	e, err := client.FindElement(by, value)
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	return e
+}
+
+// required test in sequential main client test: client_test.go
+func FillFormTest(t *testing.T) {
+	navigateLocal("form.html")
+
+	form := findElement(t, By(CSS_SELECTOR), "form[name=optional]")
+
+	unmatched, err := FillForm(form, map[string]any{
+		"checky":        true,
+		"checkedchecky": false,
+		"selectomatic":  "Four",
+		"multi":         []string{"ham", "onion gravy"},
+		"snack":         "peas",
+		"nope":          "nothing",
+	}, false)
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if len(unmatched) != 1 || unmatched[0] != "nope" {
+		t.Fatalf("unexpected unmatched keys %v", unmatched)
+	}
+
+	for id, expected := range map[string]bool{"checky": true, "checkedchecky": false, "peas": true, "cheese_and_peas": false} {
+		if findElement(t, By(ID), id).Selected() != expected {
+			t.Fatalf("%s: expected selected=%v", id, expected)
+		}
+	}
+
+	s, _ := NewSelect(findElement(t, By(NAME), "selectomatic"))
+	if v := selectedValues(t, s); len(v) != 1 || v[0] != "four" {
+		t.Fatalf("unexpected selection %v", v)
+	}
+	s, _ = NewSelect(findElement(t, By(NAME), "multi"))
+	if v := selectedValues(t, s); len(v) != 2 || v[0] != "ham" || v[1] != "onion gravy" {
+		t.Fatalf("unexpected selection %v", v)
+	}
+
+	// by id and by label
+	login := findElement(t, By(CSS_SELECTOR), "form[name=login]")
+	if _, err = FillForm(login, map[string]string{"email": "me@example.com"}, false); err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if v, _ := Property[string](findElement(t, By(ID), "email"), "value"); v != "me@example.com" {
+		t.Fatalf("unexpected email %q", v)
+	}
+
+	labelled := findElement(t, By(CSS_SELECTOR), "form[action='formPage.html']")
+	if _, err = FillForm(labelled, map[string]bool{"Label": true}, false); err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if !findElement(t, By(ID), "checkbox-with-label").Selected() {
+		t.Fatal("labelled checkbox not checked")
+	}
+}
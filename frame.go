@@ -0,0 +1,227 @@
+package marionette
+
+// frameRef identifies a child frame of a browsing context, either by its
+// index in window.frames or by the web element of its frame/iframe.
+type frameRef struct {
+	index   int
+	element string
+}
+
+func (r frameRef) params() map[string]any {
+	if r.element != "" {
+		return map[string]any{"element": r.element, "focus": true}
+	}
+	return map[string]any{"id": r.index, "focus": true}
+}
+
+// Frame is a handle on a (possibly nested) frame of the current window.
+//
+// Its methods switch to the frame before executing, so a Frame stays usable
+// after the client switched to another frame. Leave switches back to the
+// frame that was current when the Frame was entered.
+type Frame struct {
+	c    *Client
+	path []frameRef
+	prev []frameRef
+}
+
+// SwitchToFrameIndex switches to the child frame at index in window.frames.
+func (c *Client) SwitchToFrameIndex(index int) error {
+	return c.switchToChildFrame(frameRef{index: index})
+}
+
+// SwitchToFrameElement switches to the frame of a frame or iframe element.
+func (c *Client) SwitchToFrameElement(e *WebElement) error {
+	id, err := e.locatedId()
+	if err != nil {
+		return err
+	}
+	return c.switchToChildFrame(frameRef{element: id})
+}
+
+// SwitchToTopFrame switches back to the top-level document of the window.
+func (c *Client) SwitchToTopFrame() error {
+	_, err := c.tr.Send("WebDriver:SwitchToFrame", map[string]any{"id": nil, "focus": true})
+	if err != nil {
+		return err
+	}
+	c.frames = nil
+	return nil
+}
+
+func (c *Client) switchToChildFrame(ref frameRef) error {
+	_, err := c.tr.Send("WebDriver:SwitchToFrame", ref.params())
+	if err != nil {
+		return err
+	}
+	c.frames = append(c.frames[:len(c.frames):len(c.frames)], ref)
+	return nil
+}
+
+// switchToFramePath makes path the current frame, descending from the
+// current frame when possible and from the top-level document otherwise.
+func (c *Client) switchToFramePath(path []frameRef) error {
+	start := len(c.frames)
+	if !isFramePathPrefix(c.frames, path) {
+		if err := c.SwitchToTopFrame(); err != nil {
+			return err
+		}
+		start = 0
+	}
+	for _, ref := range path[start:] {
+		if err := c.switchToChildFrame(ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isFramePathPrefix(prefix, path []frameRef) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Client) enterFrame(ref frameRef) (*Frame, error) {
+	prev := c.frames
+	if err := c.switchToChildFrame(ref); err != nil {
+		return nil, err
+	}
+	return &Frame{c: c, path: c.frames, prev: prev}, nil
+}
+
+// EnterFrame switches to a child frame located with the given strategy and
+// returns a handle on it.
+func (c *Client) EnterFrame(by By, value string) (*Frame, error) {
+	e, err := c.FindElement(by, value)
+	if err != nil {
+		return nil, err
+	}
+	return c.enterFrame(frameRef{element: e.Id()})
+}
+
+// EnterFrameIndex switches to the child frame at index in window.frames and
+// returns a handle on it.
+func (c *Client) EnterFrameIndex(index int) (*Frame, error) {
+	return c.enterFrame(frameRef{index: index})
+}
+
+// EnterFrameElement switches to the frame of a frame or iframe element and
+// returns a handle on it.
+func (c *Client) EnterFrameElement(e *WebElement) (*Frame, error) {
+	id, err := e.locatedId()
+	if err != nil {
+		return nil, err
+	}
+	return c.enterFrame(frameRef{element: id})
+}
+
+// Depth returns the nesting level of the frame, 1 being a child of the
+// top-level document.
+func (f *Frame) Depth() int {
+	return len(f.path)
+}
+
+// Switch makes f the current frame.
+func (f *Frame) Switch() error {
+	return f.c.switchToFramePath(f.path)
+}
+
+// Leave switches back to the frame that was current when f was entered.
+func (f *Frame) Leave() error {
+	return f.c.switchToFramePath(f.prev)
+}
+
+// EnterFrame switches to a child frame of f located with the given strategy.
+func (f *Frame) EnterFrame(by By, value string) (*Frame, error) {
+	if err := f.Switch(); err != nil {
+		return nil, err
+	}
+	return f.c.EnterFrame(by, value)
+}
+
+// EnterFrameIndex switches to the child frame of f at index.
+func (f *Frame) EnterFrameIndex(index int) (*Frame, error) {
+	if err := f.Switch(); err != nil {
+		return nil, err
+	}
+	return f.c.EnterFrameIndex(index)
+}
+
+// FindElement finds an element in the frame.
+func (f *Frame) FindElement(by By, value string) (*WebElement, error) {
+	if err := f.Switch(); err != nil {
+		return nil, err
+	}
+	return f.c.FindElement(by, value)
+}
+
+// FindElements finds elements in the frame.
+func (f *Frame) FindElements(by By, value string) ([]*WebElement, error) {
+	if err := f.Switch(); err != nil {
+		return nil, err
+	}
+	return f.c.FindElements(by, value)
+}
+
+// FindElementInAnyFrame searches an element in the current frame and then,
+// depth-first, in all its descendant frames.
+//
+// On success, the client is left in the frame containing the element, which
+// is returned as a Frame whose Leave switches back to the original frame. On
+// failure, the original frame is restored.
+//
+// Each frame is searched with a FindElement command, so the implicit timeout
+// applies to every frame that does not contain the element.
+func (c *Client) FindElementInAnyFrame(by By, value string) (*WebElement, *Frame, error) {
+	orig := c.frames
+	e, err := c.findElementInFrames(by, value)
+	if err != nil {
+		if rerr := c.switchToFramePath(orig); rerr != nil {
+			return nil, nil, rerr
+		}
+		return nil, nil, err
+	}
+	return e, &Frame{c: c, path: c.frames, prev: orig}, nil
+}
+
+func (c *Client) findElementInFrames(by By, value string) (*WebElement, error) {
+	e, notFound := c.FindElement(by, value)
+	if notFound == nil {
+		return e, nil
+	}
+	if !IsDriverError(notFound, ERROR_NO_SUCH_ELEMENT) {
+		return nil, notFound
+	}
+
+	frames, err := c.FindElements(CSS_SELECTOR, "frame, iframe")
+	if err != nil {
+		return nil, err
+	}
+	for _, frame := range frames {
+		err = c.SwitchToFrameElement(frame)
+		if IsDriverError(err, ERROR_NO_SUCH_FRAME, ERROR_STALE_ELEMENT_REFERENCE) {
+			continue // the frame went away while searching
+		} else if err != nil {
+			return nil, err
+		}
+
+		e, err = c.findElementInFrames(by, value)
+		if err == nil {
+			return e, nil
+		} else if !IsDriverError(err, ERROR_NO_SUCH_ELEMENT) {
+			return nil, err
+		}
+
+		if err = c.SwitchToParentFrame(); err != nil {
+			return nil, err
+		}
+	}
+	return nil, notFound
+}
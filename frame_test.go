@@ -0,0 +1,91 @@
+package marionette
+
+import "testing"
+
+// required test in sequential main client test: client_test.go
+func FramesTest(t *testing.T) {
+	navigateLocal("frames.html")
+
+	err := client.SwitchToFrameIndex(0)
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if _, err = client.FindElement(By(TAG_NAME), "li"); err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	err = client.SwitchToTopFrame()
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	nested, err := client.EnterFrame(By(ID), "nested-frame")
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	table, err := nested.EnterFrame(By(ID), "table-frame")
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if table.Depth() != 2 {
+		t.Fatalf("expected depth 2, got %d", table.Depth())
+	}
+
+	// move away, the frame handle must switch back by itself
+	err = client.SwitchToTopFrame()
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	if _, err = table.FindElement(By(ID), "the-table"); err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	err = table.Leave()
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if _, err = client.FindElement(By(ID), "table-frame"); err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	err = nested.Leave()
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if _, err = client.FindElement(By(ID), "list-frame"); err != nil {
+		t.Fatalf("%#v", err)
+	}
+}
+
+// required test in sequential main client test: client_test.go
+func FindElementInAnyFrameTest(t *testing.T) {
+	navigateLocal("frames.html")
+
+	e, frame, err := client.FindElementInAnyFrame(By(ID), "the-table")
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if e.TagName() != "table" {
+		t.Fatalf("expected a table, got %v", e.TagName())
+	}
+	if frame.Depth() != 2 {
+		t.Fatalf("expected depth 2, got %d", frame.Depth())
+	}
+
+	err = frame.Leave()
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	_, _, err = client.FindElementInAnyFrame(By(ID), "non-existing-element")
+	if !IsDriverError(err, ERROR_NO_SUCH_ELEMENT) {
+		t.Fatalf("expected no such element, got %#v", err)
+	}
+
+	// the original frame must be restored
+	if _, err = client.FindElement(By(ID), "list-frame"); err != nil {
+		t.Fatalf("%#v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package marionette
+
+import "fmt"
+
+// elementLocator remembers how an element was found, to find it again once
+// it is stale.
+type elementLocator struct {
+	parent *WebElement // nil for the document
+	by     By
+	value  string
+	index  int // index in the FindElements result, -1 for FindElement
+
+	// resolve and desc replace the fields above for elements found by a
+	// Locator.
+	resolve func() (*WebElement, error)
+	desc    string
+}
+
+// String describes the locator chain, like `css selector "ul" > tag name "li"[2]`.
+func (l *elementLocator) String() string {
+	if l.resolve != nil {
+		return l.desc
+	}
+	s := fmt.Sprintf("%v %q", l.by, l.value)
+	if l.index >= 0 {
+		s += fmt.Sprintf("[%d]", l.index)
+	}
+	if l.parent != nil && l.parent.loc != nil {
+		s = l.parent.loc.String() + " > " + s
+	}
+	return s
+}
+
+// SetSelfHealing enables or disables the relocation of stale elements. It is
+// enabled by default: elements found with FindElement or FindElements are
+// located again, once, when a command fails with a stale element reference
+// error, and the command is retried.
+func (c *Client) SetSelfHealing(enabled bool) {
+	c.noHealing = !enabled
+}
+
+// OnRelocate sets a hook called each time a stale element is located again.
+// err is the relocation error, if it failed. oldId is empty for the first
+// location of elements populated by PopulateLazy.
+func (c *Client) OnRelocate(hook func(e *WebElement, oldId string, err error)) {
+	c.onRelocate = hook
+}
+
+// LocatorString describes how the element was found, or returns an empty
+// string if it was not found with FindElement or FindElements.
+func (e *WebElement) LocatorString() string {
+	if e.loc == nil {
+		return ""
+	}
+	return e.loc.String()
+}
+
+// heal calls f, and calls it again after relocating e if it failed because e
+// is stale. Lazy elements, without id yet, are located first.
+func (e *WebElement) heal(f func() error) error {
+	if _, err := e.locatedId(); err != nil {
+		return err
+	}
+	err := f()
+	if !IsDriverError(err, ERROR_STALE_ELEMENT_REFERENCE) {
+		return err
+	}
+	if e.c.noHealing || e.loc == nil {
+		return err
+	}
+	if rerr := e.relocate(); rerr != nil {
+		return err
+	}
+	return f()
+}
+
+// locatedId returns the id of e, locating it first if it is lazy.
+func (e *WebElement) locatedId() (string, error) {
+	if e.id == "" && e.loc != nil {
+		if err := e.relocate(); err != nil {
+			return "", err
+		}
+	}
+	return e.id, nil
+}
+
+// relocate finds e again with its locator and updates its id.
+func (e *WebElement) relocate() error {
+	oldId := e.id
+	found, err := e.locate()
+	if err == nil {
+		e.id = found.id
+	}
+	if e.c.onRelocate != nil {
+		e.c.onRelocate(e, oldId, err)
+	}
+	return err
+}
+
+func (e *WebElement) locate() (*WebElement, error) {
+	l := e.loc
+	if l.resolve != nil {
+		return l.resolve()
+	}
+
+	var startNode *string
+	if l.parent != nil {
+		startNode = &l.parent.id
+	}
+
+	var found *WebElement
+	find := func() (err error) {
+		if l.index < 0 {
+			found, err = e.c.findElement(l.by, l.value, startNode)
+			return
+		}
+		es, err := e.c.findElements(l.by, l.value, startNode)
+		if err != nil {
+			return err
+		}
+		if l.index >= len(es) {
+			return &DriverError{
+				ErrorType: ERROR_NO_SUCH_ELEMENT,
+				Message:   fmt.Sprintf("%v: only %d elements found", l, len(es)),
+			}
+		}
+		found = es[l.index]
+		return nil
+	}
+
+	var err error
+	if l.parent != nil {
+		err = l.parent.heal(find)
+	} else {
+		err = find()
+	}
+	return found, err
+}
+
+// send sends an element command with the element id set in params, healing
+// the element if needed.
+func (e *WebElement) send(command string, params map[string]any) (r *Response, err error) {
+	if params == nil {
+		params = map[string]any{}
+	}
+	err = e.heal(func() (err error) {
+		params["id"] = e.id
+		r, err = e.c.tr.Send(command, params)
+		return
+	})
+	return
+}
@@ -0,0 +1,68 @@
+package marionette
+
+import "testing"
+
+func TestHealing(t *testing.T) {
+	t.Run("ElementLocatorStringTest", ElementLocatorStringTest)
+}
+
+func ElementLocatorStringTest(t *testing.T) {
+	list := &WebElement{loc: &elementLocator{by: CSS_SELECTOR, value: "ul.menu", index: -1}}
+	item := &WebElement{loc: &elementLocator{parent: list, by: TAG_NAME, value: "li", index: 2}}
+
+	expected := `css selector "ul.menu" > tag name "li"[2]`
+	if s := item.LocatorString(); s != expected {
+		t.Fatalf("expected %v, got %v", expected, s)
+	}
+
+	if s := new(WebElement).LocatorString(); s != "" {
+		t.Fatalf("expected no locator, got %v", s)
+	}
+}
+
+// required test in sequential main client test: client_test.go
+func SelfHealingTest(t *testing.T) {
+	navigateLocal("ul.html")
+
+	var relocated []string
+	client.OnRelocate(func(e *WebElement, oldId string, err error) {
+		if err != nil {
+			t.Errorf("relocation of %v failed: %v", e.LocatorString(), err)
+		}
+		relocated = append(relocated, e.LocatorString())
+	})
+	defer client.OnRelocate(nil)
+
+	list, err := client.FindElement(By(TAG_NAME), "ul")
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	items, err := list.FindElements(By(TAG_NAME), "li")
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	rerender := "document.body.innerHTML = '<ul><li>First</li><li>Second again</li></ul>';"
+	_, err = client.ExecuteScript(rerender, []any{}, TIMEOUT, false)
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	if text := items[1].Text(); text != "Second again" {
+		t.Fatalf("unexpected text %q", text)
+	}
+	if len(relocated) != 2 {
+		t.Fatalf("expected the item and its list to be relocated, got %v", relocated)
+	}
+
+	client.SetSelfHealing(false)
+	defer client.SetSelfHealing(true)
+
+	_, err = client.ExecuteScript(rerender, []any{}, TIMEOUT, false)
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if _, err = items[0].Rect(); !IsDriverError(err, ERROR_STALE_ELEMENT_REFERENCE) {
+		t.Fatalf("expected a stale element reference error, got %#v", err)
+	}
+}
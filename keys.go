@@ -0,0 +1,129 @@
+package marionette
+
+import "strings"
+
+// Keys are the WebDriver special keys, encoded as code points of the Unicode
+// private use area. They can be mixed with regular text in SendKeys or used
+// as KeyDown/KeyUp values.
+//
+// https://w3c.github.io/webdriver/#keyboard-actions
+const (
+	KEY_NULL            = "\uE000"
+	KEY_CANCEL          = "\uE001"
+	KEY_HELP            = "\uE002"
+	KEY_BACKSPACE       = "\uE003"
+	KEY_TAB             = "\uE004"
+	KEY_CLEAR           = "\uE005"
+	KEY_RETURN          = "\uE006"
+	KEY_ENTER           = "\uE007"
+	KEY_SHIFT           = "\uE008"
+	KEY_CONTROL         = "\uE009"
+	KEY_ALT             = "\uE00A"
+	KEY_PAUSE           = "\uE00B"
+	KEY_ESCAPE          = "\uE00C"
+	KEY_SPACE           = "\uE00D"
+	KEY_PAGE_UP         = "\uE00E"
+	KEY_PAGE_DOWN       = "\uE00F"
+	KEY_END             = "\uE010"
+	KEY_HOME            = "\uE011"
+	KEY_ARROW_LEFT      = "\uE012"
+	KEY_ARROW_UP        = "\uE013"
+	KEY_ARROW_RIGHT     = "\uE014"
+	KEY_ARROW_DOWN      = "\uE015"
+	KEY_INSERT          = "\uE016"
+	KEY_DELETE          = "\uE017"
+	KEY_SEMICOLON       = "\uE018"
+	KEY_EQUALS          = "\uE019"
+	KEY_NUMPAD0         = "\uE01A"
+	KEY_NUMPAD1         = "\uE01B"
+	KEY_NUMPAD2         = "\uE01C"
+	KEY_NUMPAD3         = "\uE01D"
+	KEY_NUMPAD4         = "\uE01E"
+	KEY_NUMPAD5         = "\uE01F"
+	KEY_NUMPAD6         = "\uE020"
+	KEY_NUMPAD7         = "\uE021"
+	KEY_NUMPAD8         = "\uE022"
+	KEY_NUMPAD9         = "\uE023"
+	KEY_MULTIPLY        = "\uE024"
+	KEY_ADD             = "\uE025"
+	KEY_SEPARATOR       = "\uE026"
+	KEY_SUBTRACT        = "\uE027"
+	KEY_DECIMAL         = "\uE028"
+	KEY_DIVIDE          = "\uE029"
+	KEY_F1              = "\uE031"
+	KEY_F2              = "\uE032"
+	KEY_F3              = "\uE033"
+	KEY_F4              = "\uE034"
+	KEY_F5              = "\uE035"
+	KEY_F6              = "\uE036"
+	KEY_F7              = "\uE037"
+	KEY_F8              = "\uE038"
+	KEY_F9              = "\uE039"
+	KEY_F10             = "\uE03A"
+	KEY_F11             = "\uE03B"
+	KEY_F12             = "\uE03C"
+	KEY_META            = "\uE03D"
+	KEY_ZENKAKU_HANKAKU = "\uE040"
+	KEY_R_SHIFT         = "\uE050"
+	KEY_R_CONTROL       = "\uE051"
+	KEY_R_ALT           = "\uE052"
+	KEY_R_META          = "\uE053"
+	KEY_R_PAGE_UP       = "\uE054"
+	KEY_R_PAGE_DOWN     = "\uE055"
+	KEY_R_END           = "\uE056"
+	KEY_R_HOME          = "\uE057"
+	KEY_R_ARROW_LEFT    = "\uE058"
+	KEY_R_ARROW_UP      = "\uE059"
+	KEY_R_ARROW_RIGHT   = "\uE05A"
+	KEY_R_ARROW_DOWN    = "\uE05B"
+	KEY_R_INSERT        = "\uE05C"
+	KEY_R_DELETE        = "\uE05D"
+)
+
+// KeyChord returns a SendKeys sequence typing text while modifiers are held.
+// The modifiers are released by a trailing KEY_NULL.
+//
+//	e.SendKeys(KeyChord("a", KEY_CONTROL)) // select all
+func KeyChord(text string, modifiers ...string) string {
+	if len(modifiers) == 0 {
+		return text
+	}
+	return strings.Join(modifiers, "") + text + KEY_NULL
+}
+
+// Chord adds the key actions typing text while modifiers are held, releasing
+// the modifiers in reverse order afterwards.
+func (ia *InputActions) Chord(text string, modifiers ...string) {
+	for _, m := range modifiers {
+		ia.Add(KeyDown{Value: m})
+	}
+	ia.TypeText(text)
+	for i := len(modifiers) - 1; i >= 0; i-- {
+		ia.Add(KeyUp{Value: modifiers[i]})
+	}
+}
+
+// TypeText adds a key down and a key up action for each character of text.
+func (ia *InputActions) TypeText(text string) {
+	for _, r := range text {
+		ia.Add(KeyDown{Value: string(r)})
+		ia.Add(KeyUp{Value: string(r)})
+	}
+}
+
+// PrimaryModifier returns the modifier used for shortcuts on the session's
+// platform: KEY_META on macOS, KEY_CONTROL elsewhere.
+func (c Capabilities) PrimaryModifier() string {
+	switch strings.ToLower(c.PlatformName) {
+	case "mac", "macos", "darwin":
+		return KEY_META
+	default:
+		return KEY_CONTROL
+	}
+}
+
+// PrimaryModifier returns the shortcut modifier of the session's platform.
+// See Capabilities.PrimaryModifier.
+func (c *Client) PrimaryModifier() string {
+	return c.Capabilities.PrimaryModifier()
+}
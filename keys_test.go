@@ -0,0 +1,60 @@
+package marionette
+
+import "testing"
+
+func TestKeys(t *testing.T) {
+	t.Run("KeyChordTest", KeyChordTest)
+	t.Run("ChordActionsTest", ChordActionsTest)
+	t.Run("PrimaryModifierTest", PrimaryModifierTest)
+}
+
+func KeyChordTest(t *testing.T) {
+	if s := KeyChord("abc"); s != "abc" {
+		t.Fatalf("expected plain text, got %q", s)
+	}
+
+	s := KeyChord("a", KEY_CONTROL, KEY_SHIFT)
+	if s != "\uE009\uE008a\uE000" {
+		t.Fatalf("unexpected chord %q", s)
+	}
+}
+
+func ChordActionsTest(t *testing.T) {
+	var actions Actions
+	actions.Key().Chord("ab", KEY_CONTROL, KEY_SHIFT)
+
+	expected := []any{
+		KeyDown{Type: "keyDown", Value: KEY_CONTROL},
+		KeyDown{Type: "keyDown", Value: KEY_SHIFT},
+		KeyDown{Type: "keyDown", Value: "a"},
+		KeyUp{Type: "keyUp", Value: "a"},
+		KeyDown{Type: "keyDown", Value: "b"},
+		KeyUp{Type: "keyUp", Value: "b"},
+		KeyUp{Type: "keyUp", Value: KEY_SHIFT},
+		KeyUp{Type: "keyUp", Value: KEY_CONTROL},
+	}
+
+	got := actions.Actions[0].Actions
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d actions, got %d", len(expected), len(got))
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("action %d: expected %#v, got %#v", i, expected[i], got[i])
+		}
+	}
+}
+
+func PrimaryModifierTest(t *testing.T) {
+	for platform, expected := range map[string]string{
+		"linux":   KEY_CONTROL,
+		"windows": KEY_CONTROL,
+		"mac":     KEY_META,
+		"":        KEY_CONTROL,
+	} {
+		c := Capabilities{PlatformName: platform}
+		if m := c.PrimaryModifier(); m != expected {
+			t.Fatalf("platform %q: expected %q, got %q", platform, expected, m)
+		}
+	}
+}
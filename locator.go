@@ -0,0 +1,282 @@
+package marionette
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Locator describes how to find elements, as a chain of steps each searching
+// within the elements found by the previous one.
+//
+// Locators are immutable values: they can be stored in page objects and
+// combined, they are only resolved by Find, FindAll or Count.
+//
+//	settings := CSS("ul.menu").Descendant(TagName("li")).WithText("Settings")
+//	e, err := settings.Find(client)
+type Locator struct {
+	steps []locatorStep
+}
+
+type locatorStep struct {
+	by      By
+	value   string
+	query   *locatorQuery // replaces by and value when set
+	filters []locatorFilter
+	nth     int
+	hasNth  bool
+}
+
+type locatorFilter struct {
+	desc  string
+	match func(e *WebElement) (bool, error)
+}
+
+// Locate returns a locator of the elements found with the given strategy.
+func Locate(by By, value string) Locator {
+	return Locator{steps: []locatorStep{{by: by, value: value}}}
+}
+
+// CSS locates elements by CSS selector.
+func CSS(selector string) Locator {
+	return Locate(CSS_SELECTOR, selector)
+}
+
+// XPath locates elements by XPath expression.
+func XPath(expr string) Locator {
+	return Locate(XPATH, expr)
+}
+
+// TagName locates elements by tag name.
+func TagName(name string) Locator {
+	return Locate(TAG_NAME, name)
+}
+
+// ClassName locates elements by class name.
+func ClassName(name string) Locator {
+	return Locate(CLASS_NAME, name)
+}
+
+// LinkText locates links by their exact text.
+func LinkText(text string) Locator {
+	return Locate(LINK_TEXT, text)
+}
+
+// PartialLinkText locates links by a part of their text.
+func PartialLinkText(text string) Locator {
+	return Locate(PARTIAL_LINK_TEXT, text)
+}
+
+// with returns a copy of l with its last step modified by f.
+func (l Locator) with(f func(s *locatorStep)) Locator {
+	if len(l.steps) == 0 {
+		return l
+	}
+	steps := make([]locatorStep, len(l.steps))
+	copy(steps, l.steps)
+	last := &steps[len(steps)-1]
+	last.filters = append([]locatorFilter(nil), last.filters...)
+	f(last)
+	return Locator{steps: steps}
+}
+
+// Descendant returns a locator of the elements matching sub within the
+// elements matching l.
+func (l Locator) Descendant(sub Locator) Locator {
+	steps := make([]locatorStep, 0, len(l.steps)+len(sub.steps))
+	steps = append(steps, l.steps...)
+	steps = append(steps, sub.steps...)
+	return Locator{steps: steps}
+}
+
+// Filter keeps the elements for which match returns true. desc describes the
+// filter in String.
+func (l Locator) Filter(desc string, match func(e *WebElement) (bool, error)) Locator {
+	return l.with(func(s *locatorStep) {
+		s.filters = append(s.filters, locatorFilter{desc: desc, match: match})
+	})
+}
+
+// WithText keeps the elements whose text contains text.
+func (l Locator) WithText(text string) Locator {
+	return l.Filter(fmt.Sprintf("text~=%q", text), func(e *WebElement) (bool, error) {
+		return strings.Contains(e.Text(), text), nil
+	})
+}
+
+// WithAttribute keeps the elements whose attribute name is value.
+func (l Locator) WithAttribute(name, value string) Locator {
+	return l.Filter(fmt.Sprintf("%s=%q", name, value), func(e *WebElement) (bool, error) {
+		v, err := e.Attribute(name)
+		return v == value, err
+	})
+}
+
+// Visible keeps the displayed elements.
+func (l Locator) Visible() Locator {
+	return l.Filter("visible", func(e *WebElement) (bool, error) {
+		return e.Displayed(), nil
+	})
+}
+
+// Nth keeps the element at index, counting from the end if negative.
+func (l Locator) Nth(index int) Locator {
+	return l.with(func(s *locatorStep) {
+		s.nth = index
+		s.hasNth = true
+	})
+}
+
+// First keeps the first element.
+func (l Locator) First() Locator {
+	return l.Nth(0)
+}
+
+// Last keeps the last element.
+func (l Locator) Last() Locator {
+	return l.Nth(-1)
+}
+
+// String describes the locator, like
+// `css selector "ul.menu" >> tag name "li" [text~="Settings"] [2]`.
+func (l Locator) String() string {
+	parts := make([]string, len(l.steps))
+	for i, s := range l.steps {
+		part := fmt.Sprintf("%v %q", s.by, s.value)
+		if s.query != nil {
+			part = s.query.desc
+		}
+		for _, f := range s.filters {
+			part += " [" + f.desc + "]"
+		}
+		if s.hasNth {
+			part += fmt.Sprintf(" [%d]", s.nth)
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, " >> ")
+}
+
+// FindAll returns the elements matching l, searching from root (a Client, a
+// WebElement, a Frame or a Window).
+func (l Locator) FindAll(root Finder) ([]*WebElement, error) {
+	if len(l.steps) == 0 {
+		return nil, fmt.Errorf("empty locator")
+	}
+
+	roots := []Finder{root}
+	var found []*WebElement
+	for _, s := range l.steps {
+		found = found[:0:0]
+		seen := map[string]bool{}
+		for _, r := range roots {
+			es, err := s.find(r)
+			if err != nil {
+				return nil, fmt.Errorf("%v: %w", l, err)
+			}
+			for _, e := range es {
+				if seen[e.id] {
+					continue // nested roots find the same elements
+				}
+				seen[e.id] = true
+
+				ok, err := s.match(e)
+				if err != nil {
+					return nil, fmt.Errorf("%v: %w", l, err)
+				}
+				if ok {
+					found = append(found, e)
+				}
+			}
+		}
+
+		if s.hasNth {
+			i := s.nth
+			if i < 0 {
+				i += len(found)
+			}
+			if i < 0 || i >= len(found) {
+				found = nil
+			} else {
+				found = found[i : i+1]
+			}
+		}
+
+		roots = make([]Finder, len(found))
+		for i, e := range found {
+			roots[i] = e
+		}
+	}
+
+	for i, e := range found {
+		l.setRelocator(e, root, i)
+	}
+	return found, nil
+}
+
+func (s locatorStep) find(root Finder) ([]*WebElement, error) {
+	if s.query != nil {
+		return s.query.find(root)
+	}
+	return root.FindElements(s.by, s.value)
+}
+
+func (s locatorStep) match(e *WebElement) (bool, error) {
+	for _, f := range s.filters {
+		ok, err := f.match(e)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Find returns the first element matching l, searching from root. It fails
+// with a "no such element" DriverError if there is none.
+func (l Locator) Find(root Finder) (*WebElement, error) {
+	es, err := l.FindAll(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(es) == 0 {
+		return nil, &DriverError{
+			ErrorType: ERROR_NO_SUCH_ELEMENT,
+			Message:   fmt.Sprintf("no element matches %v", l),
+		}
+	}
+	return es[0], nil
+}
+
+// Count returns the number of elements matching l, searching from root.
+func (l Locator) Count(root Finder) (int, error) {
+	es, err := l.FindAll(root)
+	return len(es), err
+}
+
+// setRelocator makes e relocatable with l when stale.
+func (l Locator) setRelocator(e *WebElement, root Finder, index int) {
+	e.loc = &elementLocator{
+		desc: rootDesc(root) + fmt.Sprintf("%v [%d]", l, index),
+		resolve: func() (*WebElement, error) {
+			es, err := l.FindAll(root)
+			if err != nil {
+				return nil, err
+			}
+			if index >= len(es) {
+				return nil, &DriverError{
+					ErrorType: ERROR_NO_SUCH_ELEMENT,
+					Message:   fmt.Sprintf("%v: only %d elements found", l, len(es)),
+				}
+			}
+			return es[index], nil
+		},
+	}
+}
+
+// rootDesc returns the locator of root followed by " >> " if root is an
+// element with a known locator.
+func rootDesc(root Finder) string {
+	if e, ok := root.(*WebElement); ok && e.loc != nil {
+		return e.loc.String() + " >> "
+	}
+	return ""
+}
@@ -0,0 +1,282 @@
+package marionette
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// TextMatch tells how text-based locators match text. Texts are compared
+// after trimming and collapsing their whitespaces.
+type TextMatch struct {
+	mode  string // "exact", "substring" or "regexp"
+	value string
+	flags string
+}
+
+// Exactly matches texts equal to text.
+func Exactly(text string) TextMatch {
+	return TextMatch{mode: "exact", value: text}
+}
+
+// Containing matches texts containing text, ignoring case.
+func Containing(text string) TextMatch {
+	return TextMatch{mode: "substring", value: text}
+}
+
+// Matching matches texts matched by re. The expression is evaluated by the
+// browser's JavaScript engine, so it must use the syntax common to Go and
+// JavaScript; a leading (?i) is supported.
+func Matching(re *regexp.Regexp) TextMatch {
+	src, flags := re.String(), ""
+	if strings.HasPrefix(src, "(?i)") {
+		src, flags = src[4:], "i"
+	}
+	return TextMatch{mode: "regexp", value: src, flags: flags}
+}
+
+func (m TextMatch) String() string {
+	switch m.mode {
+	case "exact":
+		return fmt.Sprintf("%q", m.value)
+	case "substring":
+		return fmt.Sprintf("~%q", m.value)
+	case "regexp":
+		return fmt.Sprintf("/%s/%s", m.value, m.flags)
+	default:
+		return "*"
+	}
+}
+
+func (m TextMatch) MarshalJSON() ([]byte, error) {
+	if m.mode == "" {
+		return []byte("null"), nil
+	}
+	return json.Marshal(map[string]string{"mode": m.mode, "value": m.value, "flags": m.flags})
+}
+
+// Role locates elements by ARIA role, explicit or implicit, and optionally by
+// accessible name. Hidden elements are ignored.
+//
+//	Role("button", Exactly("Save"))
+func Role(role string, name ...TextMatch) Locator {
+	q := &locatorQuery{kind: "role", args: []any{role, nil}, desc: fmt.Sprintf("role %q", role)}
+	if len(name) != 0 {
+		q.args[1] = name[0]
+		q.desc += " name=" + name[0].String()
+	}
+	return Locator{steps: []locatorStep{{query: q}}}
+}
+
+// Label locates form controls by the text of their label, aria-label or
+// aria-labelledby.
+func Label(text TextMatch) Locator {
+	return Locator{steps: []locatorStep{{query: &locatorQuery{
+		kind: "label", args: []any{text}, desc: "label " + text.String(),
+	}}}}
+}
+
+// Placeholder locates form controls by their placeholder.
+func Placeholder(text TextMatch) Locator {
+	return Locator{steps: []locatorStep{{query: &locatorQuery{
+		kind: "placeholder", args: []any{text}, desc: "placeholder " + text.String(),
+	}}}}
+}
+
+// Text locates the innermost visible elements whose text matches.
+func Text(text TextMatch) Locator {
+	return Locator{steps: []locatorStep{{query: &locatorQuery{
+		kind: "text", args: []any{text}, desc: "text " + text.String(),
+	}}}}
+}
+
+// TestID locates elements by their data-testid attribute.
+func TestID(id string) Locator {
+	return CSS(`[data-testid="` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(id) + `"]`)
+}
+
+// locatorQuery is a locator step resolved by locatorScript.
+type locatorQuery struct {
+	kind string
+	args []any
+	desc string
+}
+
+// executeScriptFrom executes a script taking the root of a search as first
+// argument: an element, or null for the document of a Client, Frame or
+// Window.
+func executeScriptFrom(root Finder, script string, args ...any) (*Response, error) {
+	var c *Client
+	var e *WebElement
+	switch r := root.(type) {
+	case *Client:
+		c = r
+	case *WebElement:
+		c, e = r.c, r
+	case *Frame:
+		if err := r.Switch(); err != nil {
+			return nil, err
+		}
+		c = r.c
+	case *Window:
+		if err := r.Switch(); err != nil {
+			return nil, err
+		}
+		c = r.c
+	default:
+		return nil, fmt.Errorf("can't search from a %T", root)
+	}
+
+	if e == nil {
+		return c.ExecuteScript(script, append([]any{nil}, args...), 0, false)
+	}
+	var r *Response
+	err := e.heal(func() (err error) {
+		r, err = c.ExecuteScript(script, append([]any{e}, args...), 0, false)
+		return
+	})
+	return r, err
+}
+
+func (q *locatorQuery) find(root Finder) ([]*WebElement, error) {
+	r, err := executeScriptFrom(root, locatorScript, q.kind, q.args)
+	if err != nil {
+		return nil, err
+	}
+	c, err := finderClient(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var out struct {
+		Value []map[string]string `json:"value"`
+	}
+	if err = json.Unmarshal([]byte(r.Value), &out); err != nil {
+		return nil, err
+	}
+	es := make([]*WebElement, len(out.Value))
+	for i, v := range out.Value {
+		es[i] = &WebElement{c: c, id: v[WEBDRIVER_ELEMENT_KEY]}
+	}
+	return es, nil
+}
+
+// locatorScript finds elements for a locatorQuery.
+const locatorScript = `
+let [root, kind, args] = arguments;
+root = root || document;
+
+let normalize = (s) => (s || "").replace(/\s+/g, " ").trim();
+let matches = (text, m) => {
+	if (m == null) return true;
+	text = normalize(text);
+	switch (m.mode) {
+	case "exact": return text == normalize(m.value);
+	case "substring": return text.toLowerCase().includes(normalize(m.value).toLowerCase());
+	case "regexp": return new RegExp(m.value, m.flags).test(text);
+	}
+	return false;
+};
+
+let isHidden = (el) => {
+	if (el.closest("[hidden], [aria-hidden=true]")) return true;
+	if (el.getClientRects().length == 0) return true;
+	return getComputedStyle(el).visibility != "visible";
+};
+
+let all = () => Array.from(root.querySelectorAll("*"));
+
+let implicitRole = (el) => {
+	let tag = el.localName, type = (el.getAttribute("type") || "").toLowerCase();
+	switch (tag) {
+	case "a": case "area": return el.hasAttribute("href") ? "link" : null;
+	case "article": return "article";
+	case "aside": return "complementary";
+	case "button": return "button";
+	case "dialog": return "dialog";
+	case "fieldset": return "group";
+	case "footer": return "contentinfo";
+	case "form": return "form";
+	case "h1": case "h2": case "h3": case "h4": case "h5": case "h6": return "heading";
+	case "header": return "banner";
+	case "hr": return "separator";
+	case "img": return el.getAttribute("alt") === "" ? "presentation" : "img";
+	case "li": return "listitem";
+	case "main": return "main";
+	case "nav": return "navigation";
+	case "ol": case "ul": return "list";
+	case "option": return "option";
+	case "progress": return "progressbar";
+	case "section": return "region";
+	case "select": return el.multiple || el.size > 1 ? "listbox" : "combobox";
+	case "table": return "table";
+	case "td": return "cell";
+	case "textarea": return "textbox";
+	case "th": return "columnheader";
+	case "tr": return "row";
+	case "input":
+		switch (type) {
+		case "button": case "image": case "reset": case "submit": return "button";
+		case "checkbox": return "checkbox";
+		case "radio": return "radio";
+		case "range": return "slider";
+		case "number": return "spinbutton";
+		case "search": return "searchbox";
+		case "hidden": return null;
+		default: return el.hasAttribute("list") ? "combobox" : "textbox";
+		}
+	}
+	return null;
+};
+
+let roles = (el) => {
+	let explicit = el.getAttribute("role");
+	if (explicit) return explicit.split(/\s+/);
+	let role = implicitRole(el);
+	return role ? [role] : [];
+};
+
+let byIds = (ids) => ids.split(/\s+/).map((id) => document.getElementById(id)).filter((e) => e);
+
+let labelsOf = (el) => {
+	let texts = [];
+	if (el.hasAttribute("aria-labelledby")) {
+		texts.push(byIds(el.getAttribute("aria-labelledby")).map((e) => e.textContent).join(" "));
+	}
+	if (el.hasAttribute("aria-label")) texts.push(el.getAttribute("aria-label"));
+	for (let label of el.labels || []) texts.push(label.textContent);
+	return texts;
+};
+
+let nameFromContent = ["button", "cell", "checkbox", "columnheader", "heading", "link", "listitem",
+	"menuitem", "option", "radio", "row", "switch", "tab", "tooltip", "treeitem"];
+
+let accessibleName = (el, role) => {
+	let labels = labelsOf(el).map(normalize).filter((t) => t);
+	if (labels.length) return labels[0];
+	if (el.localName == "img" || (el.localName == "input" && el.type == "image")) return el.getAttribute("alt") || "";
+	if (el.localName == "input" && ["button", "submit", "reset"].includes(el.type)) {
+		return el.value || {submit: "Submit", reset: "Reset"}[el.type] || "";
+	}
+	if (nameFromContent.includes(role)) return el.textContent;
+	return el.getAttribute("title") || "";
+};
+
+switch (kind) {
+case "role": {
+	let [role, name] = args;
+	return all().filter((el) => roles(el).includes(role) && !isHidden(el) && matches(accessibleName(el, role), name));
+}
+case "label":
+	return all().filter((el) => labelsOf(el).some((t) => matches(t, args[0])));
+case "placeholder":
+	return all().filter((el) => el.hasAttribute("placeholder") && matches(el.getAttribute("placeholder"), args[0]));
+case "text": {
+	let skip = ["head", "script", "style", "noscript", "template"];
+	let found = all().filter((el) => !skip.includes(el.localName) && !isHidden(el) && matches(el.innerText, args[0]));
+	return found.filter((el) => !found.some((other) => other != el && el.contains(other)));
+}
+}
+throw new Error("unknown locator kind " + kind);
+`
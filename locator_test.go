@@ -0,0 +1,200 @@
+package marionette
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+)
+
+// listFinder finds the same elements whatever the strategy.
+type listFinder []string
+
+func (f listFinder) FindElement(by By, value string) (*WebElement, error) {
+	es, _ := f.FindElements(by, value)
+	return es[0], nil
+}
+
+func (f listFinder) FindElements(by By, value string) ([]*WebElement, error) {
+	var es []*WebElement
+	for _, id := range f {
+		es = append(es, &WebElement{id: id})
+	}
+	return es, nil
+}
+
+func TestLocator(t *testing.T) {
+	t.Run("LocatorStringTest", LocatorStringTest)
+	t.Run("LocatorImmutableTest", LocatorImmutableTest)
+	t.Run("LocatorFindAllTest", LocatorFindAllTest)
+}
+
+func LocatorStringTest(t *testing.T) {
+	l := CSS("ul.menu").Descendant(TagName("li")).WithText("Settings").Nth(2)
+	expected := `css selector "ul.menu" >> tag name "li" [text~="Settings"] [2]`
+	if s := l.String(); s != expected {
+		t.Fatalf("expected %v, got %v", expected, s)
+	}
+}
+
+func LocatorImmutableTest(t *testing.T) {
+	items := CSS("li")
+	first := items.First()
+	visible := items.Visible()
+
+	if s := items.String(); s != `css selector "li"` {
+		t.Fatalf("base locator was modified: %v", s)
+	}
+	if s := first.String(); s != `css selector "li" [0]` {
+		t.Fatalf("unexpected locator: %v", s)
+	}
+	if s := visible.Last().String(); s != `css selector "li" [visible] [-1]` {
+		t.Fatalf("unexpected locator: %v", s)
+	}
+	if s := visible.String(); s != `css selector "li" [visible]` {
+		t.Fatalf("filtered locator was modified: %v", s)
+	}
+}
+
+func LocatorFindAllTest(t *testing.T) {
+	root := listFinder{"a", "b", "c", "d"}
+	notB := CSS("li").Filter("not b", func(e *WebElement) (bool, error) {
+		return e.Id() != "b", nil
+	})
+
+	es, err := notB.FindAll(root)
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if len(es) != 3 || es[1].Id() != "c" {
+		t.Fatalf("unexpected elements %v", es)
+	}
+	if es[1].LocatorString() != `css selector "li" [not b] [1]` {
+		t.Fatalf("unexpected element locator %v", es[1].LocatorString())
+	}
+
+	e, err := notB.Last().Find(root)
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if e.Id() != "d" {
+		t.Fatalf("expected d, got %v", e.Id())
+	}
+
+	n, err := notB.Nth(5).Count(root)
+	if err != nil || n != 0 {
+		t.Fatalf("expected no element, got %d, %v", n, err)
+	}
+
+	_, err = notB.Nth(5).Find(root)
+	if !IsDriverError(err, ERROR_NO_SUCH_ELEMENT) {
+		t.Fatalf("expected no such element, got %#v", err)
+	}
+	t.Log(err)
+}
+
+// required test in sequential main client test: client_test.go
+func LocatorTest(t *testing.T) {
+	navigateLocal("table.html")
+
+	names := CSS("#the-table").Descendant(CSS("tbody tr")).First().Descendant(TagName("td")).Nth(1)
+	e, err := names.Find(client)
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if text := e.Text(); text != "John Doe" {
+		t.Fatalf("unexpected text %q", text)
+	}
+
+	n, err := TagName("td").WithText("Alice").Count(client)
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected one cell, got %d", n)
+	}
+}
+
+func TestLocatorQuery(t *testing.T) {
+	t.Run("TextMatchTest", TextMatchTest)
+	t.Run("QueryLocatorStringTest", QueryLocatorStringTest)
+}
+
+func TextMatchTest(t *testing.T) {
+	for _, tc := range []struct {
+		m    TextMatch
+		s    string
+		json string
+	}{
+		{Exactly("Save"), `"Save"`, `{"flags":"","mode":"exact","value":"Save"}`},
+		{Containing("save"), `~"save"`, `{"flags":"","mode":"substring","value":"save"}`},
+		{Matching(regexp.MustCompile(`(?i)^save\b`)), `/^save\b/i`, `{"flags":"i","mode":"regexp","value":"^save\\b"}`},
+		{TextMatch{}, `*`, `null`},
+	} {
+		if s := tc.m.String(); s != tc.s {
+			t.Fatalf("expected %v, got %v", tc.s, s)
+		}
+		b, err := json.Marshal(tc.m)
+		if err != nil {
+			t.Fatalf("%#v", err)
+		}
+		if string(b) != tc.json {
+			t.Fatalf("expected %v, got %v", tc.json, string(b))
+		}
+	}
+}
+
+func QueryLocatorStringTest(t *testing.T) {
+	for l, expected := range map[*Locator]string{
+		ptr(Role("button", Exactly("Save"))):            `role "button" name="Save"`,
+		ptr(CSS("form").Descendant(Role("textbox"))):    `css selector "form" >> role "textbox"`,
+		ptr(Label(Containing("user")).First()):          `label ~"user" [0]`,
+		ptr(TestID(`say "hi"`)):                         `css selector "[data-testid=\"say \\\"hi\\\"\"]"`,
+		ptr(Text(Matching(regexp.MustCompile(`^a+$`)))): `text /^a+$/`,
+	} {
+		if s := l.String(); s != expected {
+			t.Fatalf("expected %v, got %v", expected, s)
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// required test in sequential main client test: client_test.go
+func QueryLocatorTest(t *testing.T) {
+	navigateLocal("roles.html")
+
+	for l, id := range map[*Locator]string{
+		ptr(Label(Exactly("User name"))):           "user",
+		ptr(Label(Containing("password"))):         "password",
+		ptr(Placeholder(Exactly("jdoe"))):          "user",
+		ptr(Role("searchbox", Containing("site"))): "search",
+		ptr(Text(Exactly("jdoe"))):                 "who",
+	} {
+		e, err := l.Find(client)
+		if err != nil {
+			t.Fatalf("%v: %#v", l, err)
+		}
+		if v, _ := e.Attribute("id"); v != id {
+			t.Fatalf("%v: expected #%v, got #%v", l, id, v)
+		}
+	}
+
+	for l, count := range map[*Locator]int{
+		ptr(Role("link")):   2,
+		ptr(Role("button")): 2, // the reset button is hidden
+		ptr(Role("button", Matching(regexp.MustCompile(`^Save`)))):    1,
+		ptr(CSS("nav").Descendant(Role("link", Exactly("Settings")))): 1,
+		ptr(Role("heading", Exactly("Account"))):                      1,
+		ptr(TestID("cancel")):                                         1,
+	} {
+		n, err := l.Count(client)
+		if err != nil {
+			t.Fatalf("%v: %#v", l, err)
+		}
+		if n != count {
+			t.Fatalf("%v: expected %d elements, got %d", l, count, n)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package marionette
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// PAGE_TAG is the struct tag used by Populate.
+const PAGE_TAG = "marionette"
+
+var pageStrategies = map[string]func(value string) Locator{
+	"id":           func(v string) Locator { return Locate(ID, v) },
+	"name":         func(v string) Locator { return Locate(NAME, v) },
+	"class":        ClassName,
+	"tag":          TagName,
+	"css":          CSS,
+	"xpath":        XPath,
+	"link":         LinkText,
+	"partial-link": PartialLinkText,
+	"testid":       TestID,
+}
+
+// ParseLocator parses a locator in the "strategy=value" form used by page
+// object tags. The strategy is one of id, name, class, tag, css, xpath, link,
+// partial-link or testid; without strategy, value is a CSS selector.
+func ParseLocator(s string) (Locator, error) {
+	strategy, value, found := strings.Cut(s, "=")
+	if !found {
+		return CSS(s), nil
+	}
+	locate, ok := pageStrategies[strategy]
+	if !ok {
+		// not a strategy, as in "input[name=user]"
+		if strings.ContainsAny(strategy, " #.[:>~+*") {
+			return CSS(s), nil
+		}
+		return Locator{}, fmt.Errorf("unknown locator strategy %q", strategy)
+	}
+	return locate(value), nil
+}
+
+// PageFieldError is the error of a page object field.
+type PageFieldError struct {
+	// Field is the path of the field, like "Login.User".
+	Field string
+	Tag   string
+	Err   error
+}
+
+func (e *PageFieldError) Error() string {
+	return fmt.Sprintf("%s (%s): %v", e.Field, e.Tag, e.Err)
+}
+
+func (e *PageFieldError) Unwrap() error {
+	return e.Err
+}
+
+// PageError lists the fields Populate or Extract failed to fill.
+type PageError struct {
+	Fields []*PageFieldError
+}
+
+func (e *PageError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return fmt.Sprintf("%d page fields failed: %s", len(e.Fields), strings.Join(msgs, "; "))
+}
+
+func (e *PageError) Unwrap() []error {
+	errs := make([]error, len(e.Fields))
+	for i, f := range e.Fields {
+		errs[i] = f
+	}
+	return errs
+}
+
+// Populate fills the page object pointed by page with the elements found
+// from root, a Client or a WebElement.
+//
+// Fields are tagged with a locator in the ParseLocator form:
+//
+//	type LoginPage struct {
+//		Form struct {
+//			User   *WebElement `marionette:"css=input[name=user]"`
+//			Submit *WebElement `marionette:"css=button[type=submit]"`
+//		} `marionette:"id=login"`
+//		Errors []*WebElement  `marionette:"class=error"`
+//		Menu   Locator        `marionette:"css=nav a"`
+//	}
+//
+// *WebElement fields are set to the first element found, []*WebElement
+// fields to all of them and Locator fields to the parsed locator. Struct
+// fields are populated recursively, searching in the element found with
+// their tag if any. Tagged pointers to structs are allocated if nil and
+// populated the same way, untagged ones only if not nil. Untagged fields of
+// other types are left untouched.
+//
+// All the fields are processed, then a PageError lists the failed ones.
+func Populate(root Finder, page any) error {
+	return populate(root, page, false)
+}
+
+// PopulateLazy is like Populate, but elements are only searched when first
+// used, through the relocation of stale elements (see SetSelfHealing).
+// []*WebElement fields are still searched immediately.
+func PopulateLazy(root Finder, page any) error {
+	return populate(root, page, true)
+}
+
+func populate(root Finder, page any, lazy bool) error {
+	v := reflect.ValueOf(page)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("page must be a pointer to a struct, not %T", page)
+	}
+
+	p := &pagePopulator{lazy: lazy, visiting: map[pageVisit]bool{}}
+	if lazy {
+		var err error
+		if p.c, err = finderClient(root); err != nil {
+			return err
+		}
+	}
+	p.populate(root, v.Elem(), "")
+	if len(p.errs) != 0 {
+		return &PageError{Fields: p.errs}
+	}
+	return nil
+}
+
+var (
+	webElementType  = reflect.TypeOf((*WebElement)(nil))
+	webElementsType = reflect.TypeOf([]*WebElement(nil))
+	locatorType     = reflect.TypeOf(Locator{})
+)
+
+type pagePopulator struct {
+	lazy bool
+	c    *Client
+	errs []*PageFieldError
+	// visiting are the structs being populated, to stop on cycles
+	visiting map[pageVisit]bool
+}
+
+// pageVisit is a struct being populated. The type is needed as a struct
+// shares its address with its first field.
+type pageVisit struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+func (p *pagePopulator) fail(field, tag string, err error) {
+	p.errs = append(p.errs, &PageFieldError{Field: field, Tag: tag, Err: err})
+}
+
+func (p *pagePopulator) populate(root Finder, v reflect.Value, prefix string) {
+	t := v.Type()
+	visit := pageVisit{v.Addr().Pointer(), t}
+	if p.visiting[visit] {
+		return
+	}
+	p.visiting[visit] = true
+	defer delete(p.visiting, visit)
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := prefix + sf.Name
+		fv := v.Field(i)
+		tag, tagged := sf.Tag.Lookup(PAGE_TAG)
+
+		isStruct := sf.Type.Kind() == reflect.Struct && sf.Type != locatorType
+		isStructPtr := sf.Type.Kind() == reflect.Pointer && sf.Type.Elem().Kind() == reflect.Struct && sf.Type != webElementType
+
+		if !tagged {
+			if isStruct {
+				p.populate(root, fv, name+".")
+			} else if isStructPtr && !fv.IsNil() {
+				p.populate(root, fv.Elem(), name+".")
+			}
+			continue
+		}
+
+		l, err := ParseLocator(tag)
+		if err != nil {
+			p.fail(name, tag, err)
+			continue
+		}
+
+		switch {
+		case sf.Type == locatorType:
+			fv.Set(reflect.ValueOf(l))
+
+		case sf.Type == webElementType:
+			e, err := p.find(root, l)
+			if err != nil {
+				p.fail(name, tag, err)
+				continue
+			}
+			fv.Set(reflect.ValueOf(e))
+
+		case sf.Type == webElementsType:
+			es, err := l.FindAll(root)
+			if err != nil {
+				p.fail(name, tag, err)
+				continue
+			}
+			fv.Set(reflect.ValueOf(es))
+
+		case isStruct, isStructPtr:
+			e, err := p.find(root, l)
+			if err != nil {
+				p.fail(name, tag, err)
+				continue
+			}
+			if isStructPtr {
+				if fv.IsNil() {
+					if p.expanding(sf.Type.Elem()) {
+						p.fail(name, tag, fmt.Errorf("recursive type %v", sf.Type.Elem()))
+						continue
+					}
+					fv.Set(reflect.New(sf.Type.Elem()))
+				}
+				fv = fv.Elem()
+			}
+			p.populate(e, fv, name+".")
+
+		default:
+			p.fail(name, tag, fmt.Errorf("unsupported field type %v", sf.Type))
+		}
+	}
+}
+
+// expanding returns true if a struct of type t is being populated.
+func (p *pagePopulator) expanding(t reflect.Type) bool {
+	for visit := range p.visiting {
+		if visit.typ == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *pagePopulator) find(root Finder, l Locator) (*WebElement, error) {
+	if !p.lazy {
+		return l.Find(root)
+	}
+	return &WebElement{c: p.c, loc: &elementLocator{
+		desc:    rootDesc(root) + l.String(),
+		resolve: func() (*WebElement, error) { return l.Find(root) },
+	}}, nil
+}
+
+// finderClient returns the client behind a Finder.
+func finderClient(root Finder) (*Client, error) {
+	switch r := root.(type) {
+	case *Client:
+		return r, nil
+	case *WebElement:
+		return r.c, nil
+	case *Frame:
+		return r.c, nil
+	case *Window:
+		return r.c, nil
+	}
+	return nil, errors.New("unsupported root type " + reflect.TypeOf(root).String())
+}
@@ -0,0 +1,182 @@
+package marionette
+
+import (
+	"errors"
+	"testing"
+)
+
+type loginPage struct {
+	Form struct {
+		Email  *WebElement `marionette:"css=input[type=email]"`
+		Submit *WebElement `marionette:"id=submitButton"`
+	} `marionette:"name=login"`
+	Options *struct {
+		Checkboxes []*WebElement `marionette:"input[type=checkbox]"`
+		Cheese     Locator       `marionette:"id=cheeseLiker"`
+	} `marionette:"css=form[name=optional]"`
+	Search *WebElement `marionette:"id=vsearchGadget"`
+
+	Untouched string
+	private   *WebElement `marionette:"css=p"`
+}
+
+func TestPage(t *testing.T) {
+	t.Run("ParseLocatorTest", ParseLocatorTest)
+	t.Run("PopulateLazyTest", PopulateLazyTest)
+	t.Run("PopulateErrorsTest", PopulateErrorsTest)
+	t.Run("PopulateCycleTest", PopulateCycleTest)
+}
+
+func ParseLocatorTest(t *testing.T) {
+	for s, expected := range map[string]string{
+		"css=#login input[name=user]": `css selector "#login input[name=user]"`,
+		"input[name=user]":            `css selector "input[name=user]"`,
+		"#login":                      `css selector "#login"`,
+		"id=login":                    `id "login"`,
+		"xpath=//a[@href='x']":        `xpath "//a[@href='x']"`,
+		"partial-link=more":           `partial link text "more"`,
+		"testid=save":                 `css selector "[data-testid=\"save\"]"`,
+	} {
+		l, err := ParseLocator(s)
+		if err != nil {
+			t.Fatalf("%s: %v", s, err)
+		}
+		if l.String() != expected {
+			t.Fatalf("%s: expected %v, got %v", s, expected, l)
+		}
+	}
+
+	if _, err := ParseLocator("foo=bar"); err == nil {
+		t.Fatal("expected an unknown strategy error")
+	}
+}
+
+func PopulateLazyTest(t *testing.T) {
+	var page struct {
+		Form struct {
+			Email  *WebElement `marionette:"css=input[type=email]"`
+			Submit *WebElement `marionette:"id=submitButton"`
+		} `marionette:"name=login"`
+		Options *struct {
+			Cheese Locator `marionette:"id=cheeseLiker"`
+		} `marionette:"css=form[name=optional]"`
+
+		private *WebElement `marionette:"css=p"`
+	}
+
+	// nothing is sent to the unconnected client
+	err := PopulateLazy(client, &page)
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	if page.Form.Email == nil || page.Form.Email.id != "" {
+		t.Fatalf("expected a lazy element, got %#v", page.Form.Email)
+	}
+	if l := page.Form.Submit.LocatorString(); l != `name "login" >> id "submitButton"` {
+		t.Fatalf("unexpected locator %v", l)
+	}
+	if page.Options == nil || page.Options.Cheese.String() != `id "cheeseLiker"` {
+		t.Fatalf("unexpected options %#v", page.Options)
+	}
+	if page.private != nil {
+		t.Fatal("unexported field was populated")
+	}
+}
+
+func PopulateErrorsTest(t *testing.T) {
+	var page struct {
+		A *WebElement `marionette:"id=a"`
+		B struct {
+			C []*WebElement `marionette:"tag=li"`
+		}
+		D int     `marionette:"id=d"`
+		E Locator `marionette:"id=e"`
+	}
+	err := Populate(fakeFinder{ReturnError: true}, &page)
+
+	var pe *PageError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected a PageError, got %#v", err)
+	}
+	if len(pe.Fields) != 3 {
+		t.Fatalf("expected 3 failed fields, got %v", pe)
+	}
+	for i, field := range []string{"A", "B.C", "D"} {
+		if pe.Fields[i].Field != field {
+			t.Fatalf("expected field %v to fail, got %v", field, pe.Fields[i])
+		}
+	}
+	if page.E.String() != `id "e"` {
+		t.Fatalf("unexpected locator %v", page.E)
+	}
+	t.Log(err)
+
+	if err = Populate(client, page); err == nil {
+		t.Fatal("expected an error for a non-pointer page")
+	}
+}
+
+type pageNode struct {
+	Title  *WebElement `marionette:"css=h1"`
+	Parent *pageNode
+	Child  *pageNode `marionette:"css=.child"`
+	Client *Client
+}
+
+func PopulateCycleTest(t *testing.T) {
+	node := &pageNode{}
+	node.Parent = node
+	err := PopulateLazy(client, node)
+
+	var pe *PageError
+	if !errors.As(err, &pe) || len(pe.Fields) != 1 || pe.Fields[0].Field != "Child" {
+		t.Fatalf("expected an error on the recursive Child field, got %v", err)
+	}
+	if node.Title == nil || node.Parent != node {
+		t.Fatalf("unexpected node %#v", node)
+	}
+	if node.Client != nil {
+		t.Fatal("untagged pointer was allocated")
+	}
+
+	var page struct {
+		Node    pageNode
+		Missing *pageNode
+	}
+	if err = PopulateLazy(client, &page); err == nil {
+		t.Fatal("expected an error on the recursive Node.Child field")
+	}
+	if page.Node.Title == nil || page.Missing != nil {
+		t.Fatalf("unexpected page %#v", page)
+	}
+}
+
+// required test in sequential main client test: client_test.go
+func PageObjectTest(t *testing.T) {
+	navigateLocal("form.html")
+
+	var page loginPage
+	err := Populate(client, &page)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if len(page.Options.Checkboxes) != 4 {
+		t.Fatalf("expected 4 checkboxes, got %d", len(page.Options.Checkboxes))
+	}
+	if v, _ := page.Form.Submit.Attribute("value"); v != "Hello there" {
+		t.Fatalf("unexpected submit button %v", v)
+	}
+
+	var lazy loginPage
+	err = PopulateLazy(client, &lazy)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err = lazy.Form.Email.SendKeys("lazy@example.com"); err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if v, _ := lazy.Form.Email.PropertyString("value"); v != "lazy@example.com" {
+		t.Fatalf("unexpected email value %q", v)
+	}
+}
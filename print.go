@@ -0,0 +1,103 @@
+package marionette
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Print orientations.
+const (
+	PRINT_PORTRAIT  = "portrait"
+	PRINT_LANDSCAPE = "landscape"
+)
+
+// PrintPage is a page size in centimeters.
+type PrintPage struct {
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// Common page sizes.
+var (
+	PageA4     = PrintPage{Width: 21, Height: 29.7}
+	PageLetter = PrintPage{Width: 21.59, Height: 27.94}
+	PageLegal  = PrintPage{Width: 21.59, Height: 35.56}
+)
+
+// PrintMargins are page margins in centimeters. Zero margins use the
+// browser default of 1cm.
+type PrintMargins struct {
+	Top    float64 `json:"top,omitempty"`
+	Bottom float64 `json:"bottom,omitempty"`
+	Left   float64 `json:"left,omitempty"`
+	Right  float64 `json:"right,omitempty"`
+}
+
+// PrintOptions are the parameters of PrintPDF. Zero values use the browser
+// defaults: portrait US letter pages with 1cm margins, at scale 1, shrunk to
+// fit the page width.
+type PrintOptions struct {
+	// Orientation is PRINT_PORTRAIT or PRINT_LANDSCAPE.
+	Orientation string
+	// Scale is the zoom factor, from 0.1 to 2.
+	Scale float64
+	// Background prints the background colors and images.
+	Background bool
+	Page       *PrintPage
+	Margins    *PrintMargins
+	// PageRanges are the pages to print, like "1", "3-5" or "-2".
+	PageRanges []string
+	// NoShrinkToFit disables the shrinking of content to the page width.
+	NoShrinkToFit bool
+}
+
+func (o PrintOptions) params() (map[string]any, error) {
+	params := map[string]any{
+		"background":  o.Background,
+		"shrinkToFit": !o.NoShrinkToFit,
+	}
+
+	switch o.Orientation {
+	case "":
+	case PRINT_PORTRAIT, PRINT_LANDSCAPE:
+		params["orientation"] = o.Orientation
+	default:
+		return nil, fmt.Errorf("invalid print orientation %q", o.Orientation)
+	}
+
+	if o.Scale != 0 {
+		if o.Scale < 0.1 || o.Scale > 2 {
+			return nil, fmt.Errorf("print scale %v is not between 0.1 and 2", o.Scale)
+		}
+		params["scale"] = o.Scale
+	}
+	if o.Page != nil {
+		params["page"] = o.Page
+	}
+	if o.Margins != nil {
+		params["margin"] = o.Margins
+	}
+	if len(o.PageRanges) != 0 {
+		params["pageRanges"] = o.PageRanges
+	}
+	return params, nil
+}
+
+// PrintPDF prints the current page to PDF.
+func (c *Client) PrintPDF(opts PrintOptions) ([]byte, error) {
+	params, err := opts.params()
+	if err != nil {
+		return nil, err
+	}
+	r, err := c.tr.Send("WebDriver:Print", params)
+	if err != nil {
+		return nil, err
+	}
+	var out struct {
+		Value []byte `json:"value"`
+	}
+	if err = json.Unmarshal([]byte(r.Value), &out); err != nil {
+		return nil, err
+	}
+	return out.Value, nil
+}
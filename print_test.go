@@ -0,0 +1,73 @@
+package marionette
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPrint(t *testing.T) {
+	t.Run("PrintParamsTest", PrintParamsTest)
+}
+
+func PrintParamsTest(t *testing.T) {
+	params, err := PrintOptions{}.params()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]any{"background": false, "shrinkToFit": true}
+	if !reflect.DeepEqual(params, expected) {
+		t.Fatalf("unexpected params %v", params)
+	}
+
+	params, err = PrintOptions{
+		Orientation:   PRINT_LANDSCAPE,
+		Scale:         0.5,
+		Background:    true,
+		Page:          &PageA4,
+		Margins:       &PrintMargins{Top: 2},
+		PageRanges:    []string{"1-2", "4"},
+		NoShrinkToFit: true,
+	}.params()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = map[string]any{
+		"orientation": PRINT_LANDSCAPE,
+		"scale":       0.5,
+		"background":  true,
+		"page":        &PageA4,
+		"margin":      &PrintMargins{Top: 2},
+		"pageRanges":  []string{"1-2", "4"},
+		"shrinkToFit": false,
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Fatalf("unexpected params %v", params)
+	}
+
+	// unset margins keep the browser defaults
+	if b, _ := json.Marshal(&PrintMargins{Top: 2}); string(b) != `{"top":2}` {
+		t.Fatalf("unexpected margins %s", b)
+	}
+
+	if _, err = (PrintOptions{Orientation: "sideways"}).params(); err == nil {
+		t.Fatal("expected an orientation error")
+	}
+	if _, err = (PrintOptions{Scale: 3}).params(); err == nil {
+		t.Fatal("expected a scale error")
+	}
+}
+
+// required test in sequential main client test: client_test.go
+func PrintPDFTest(t *testing.T) {
+	navigateLocal("table.html")
+
+	pdf, err := client.PrintPDF(PrintOptions{Orientation: PRINT_LANDSCAPE, Page: &PageA4, Background: true})
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+		t.Fatalf("not a PDF (%d bytes)", len(pdf))
+	}
+}
@@ -0,0 +1,306 @@
+package marionette
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// StateChangingCommands are the commands after which a Screencast recording
+// after commands captures a frame.
+var StateChangingCommands = map[string]bool{
+	"WebDriver:Navigate":         true,
+	"WebDriver:Back":             true,
+	"WebDriver:Forward":          true,
+	"WebDriver:Refresh":          true,
+	"WebDriver:ElementClick":     true,
+	"WebDriver:ElementClear":     true,
+	"WebDriver:ElementSendKeys":  true,
+	"WebDriver:PerformActions":   true,
+	"WebDriver:ReleaseActions":   true,
+	"WebDriver:NewWindow":        true,
+	"WebDriver:CloseWindow":      true,
+	"WebDriver:SwitchToWindow":   true,
+	"WebDriver:SetWindowRect":    true,
+	"WebDriver:MaximizeWindow":   true,
+	"WebDriver:MinimizeWindow":   true,
+	"WebDriver:FullscreenWindow": true,
+	"WebDriver:AcceptAlert":      true,
+	"WebDriver:DismissAlert":     true,
+	"WebDriver:SendAlertText":    true,
+	"WebDriver:AddCookie":        true,
+	"WebDriver:DeleteCookie":     true,
+	"WebDriver:DeleteAllCookies": true,
+}
+
+// ScreencastFrame is a frame of a Screencast.
+type ScreencastFrame struct {
+	// Time is the capture time of the frame.
+	Time time.Time
+	// Command is the command after which the frame was captured, empty for
+	// frames captured at intervals.
+	Command string
+	// PNG is the screenshot of the viewport.
+	PNG []byte
+}
+
+// Image decodes the frame.
+func (f ScreencastFrame) Image() (image.Image, error) {
+	return png.Decode(bytes.NewReader(f.PNG))
+}
+
+// Screencast records screenshots of a session, at intervals or after state
+// changing commands, to see what happened during a failing test.
+//
+// A frame identical to the previous one is dropped, so idle periods don't
+// bloat the recording.
+//
+//	cast := NewScreencast(client).AfterCommands()
+//	cast.Start()
+//	defer cast.WriteGIFFile("failure.gif")
+//	defer cast.Stop()
+type Screencast struct {
+	c             *Client
+	interval      time.Duration
+	afterCommands bool
+	maxFrames     int
+
+	mu       sync.Mutex
+	frames   []ScreencastFrame
+	lastHash [sha256.Size]byte
+	err      error
+
+	removeHook func()
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+// NewScreencast returns a Screencast of the session of c. It records nothing
+// until started.
+func NewScreencast(c *Client) *Screencast {
+	return &Screencast{c: c}
+}
+
+// Every captures a frame at the given interval.
+func (s *Screencast) Every(interval time.Duration) *Screencast {
+	s.interval = interval
+	return s
+}
+
+// AfterCommands captures a frame after each of the StateChangingCommands.
+func (s *Screencast) AfterCommands() *Screencast {
+	s.afterCommands = true
+	return s
+}
+
+// MaxFrames keeps only the last n frames (if positive).
+func (s *Screencast) MaxFrames(n int) *Screencast {
+	s.maxFrames = n
+	return s
+}
+
+// Start starts the recording, with a first frame.
+func (s *Screencast) Start() error {
+	if s.stop != nil {
+		return fmt.Errorf("screencast already started")
+	}
+	if err := s.capture(""); err != nil {
+		return err
+	}
+
+	if s.afterCommands {
+		s.removeHook = s.c.tr.addHook(&commandHook{
+			after: func(command string, _ any, _ *Response, _ error, _ time.Duration, _ any) {
+				if StateChangingCommands[command] {
+					s.capture(command)
+				}
+			},
+		})
+	}
+
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run()
+	return nil
+}
+
+func (s *Screencast) run() {
+	defer close(s.done)
+	if s.interval <= 0 {
+		<-s.stop
+		return
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.capture("")
+		}
+	}
+}
+
+// Stop stops the recording. It returns the first error that happened while
+// capturing frames, if any.
+func (s *Screencast) Stop() error {
+	if s.stop == nil {
+		return nil
+	}
+	if s.removeHook != nil {
+		s.removeHook()
+		s.removeHook = nil
+	}
+	close(s.stop)
+	<-s.done
+	s.stop = nil
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Frames returns the recorded frames.
+func (s *Screencast) Frames() []ScreencastFrame {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]ScreencastFrame(nil), s.frames...)
+}
+
+// capture takes a screenshot of the viewport without going through the
+// command hooks, and records it unless it is a duplicate.
+func (s *Screencast) capture(command string) error {
+	data, err := s.c.captureViewport()
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		if s.err == nil {
+			s.err = err
+		}
+		return err
+	}
+	s.add(ScreencastFrame{Time: now, Command: command, PNG: data})
+	return nil
+}
+
+// add records f unless it is identical to the last frame.
+func (s *Screencast) add(f ScreencastFrame) {
+	hash := sha256.Sum256(f.PNG)
+	if len(s.frames) != 0 && hash == s.lastHash {
+		return
+	}
+	s.lastHash = hash
+
+	s.frames = append(s.frames, f)
+	if s.maxFrames > 0 && len(s.frames) > s.maxFrames {
+		s.frames = append(s.frames[:0:0], s.frames[len(s.frames)-s.maxFrames:]...)
+	}
+}
+
+// captureViewport takes a PNG screenshot of the viewport without calling
+// the transport hooks.
+func (c *Client) captureViewport() ([]byte, error) {
+	r, err := c.tr.send("WebDriver:TakeScreenshot", ScreenshotOptions{}.params())
+	if err != nil {
+		return nil, err
+	}
+	var out struct {
+		Value []byte `json:"value"`
+	}
+	err = json.Unmarshal([]byte(r.Value), &out)
+	return out.Value, err
+}
+
+// lastFrameDelay is the display duration of the last frame of a GIF.
+const lastFrameDelay = time.Second
+
+// WriteGIF encodes the frames as an animated GIF, each frame being displayed
+// until the capture time of the next one.
+func (s *Screencast) WriteGIF(w io.Writer) error {
+	frames := s.Frames()
+	if len(frames) == 0 {
+		return fmt.Errorf("no frame recorded")
+	}
+
+	anim := &gif.GIF{}
+	for i, f := range frames {
+		img, err := f.Image()
+		if err != nil {
+			return err
+		}
+		b := img.Bounds()
+		if b.Dx() > anim.Config.Width {
+			anim.Config.Width = b.Dx()
+		}
+		if b.Dy() > anim.Config.Height {
+			anim.Config.Height = b.Dy()
+		}
+
+		p := image.NewPaletted(image.Rect(0, 0, b.Dx(), b.Dy()), palette.Plan9)
+		draw.FloydSteinberg.Draw(p, p.Rect, img, b.Min)
+
+		delay := lastFrameDelay
+		if i+1 < len(frames) {
+			delay = frames[i+1].Time.Sub(f.Time)
+		}
+
+		anim.Image = append(anim.Image, p)
+		anim.Delay = append(anim.Delay, gifDelay(delay))
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// gifDelay converts d to hundredths of second, at least 1.
+func gifDelay(d time.Duration) int {
+	delay := int(d / (10 * time.Millisecond))
+	if delay < 1 {
+		delay = 1
+	}
+	return delay
+}
+
+// WriteGIFFile writes the animated GIF of the frames to path.
+func (s *Screencast) WriteGIFFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = s.WriteGIF(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WritePNGs writes the frames to dir as PNG files, named after their index
+// and their time since the first frame, like "0003-000001250ms.png".
+func (s *Screencast) WritePNGs(dir string) error {
+	frames := s.Frames()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for i, f := range frames {
+		offset := f.Time.Sub(frames[0].Time).Milliseconds()
+		name := filepath.Join(dir, fmt.Sprintf("%04d-%09dms.png", i, offset))
+		if err := os.WriteFile(name, f.PNG, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
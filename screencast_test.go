@@ -0,0 +1,134 @@
+package marionette
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestScreencast(t *testing.T) {
+	t.Run("ScreencastDedupTest", ScreencastDedupTest)
+	t.Run("ScreencastGIFTest", ScreencastGIFTest)
+	t.Run("ScreencastPNGsTest", ScreencastPNGsTest)
+}
+
+func pngFrame(t *testing.T, c color.Color) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 3))
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 4; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func testScreencast(t *testing.T) *Screencast {
+	s := NewScreencast(client)
+	start := time.Now()
+	for i, c := range []color.Color{color.White, color.White, color.Black, color.Black, color.White} {
+		s.add(ScreencastFrame{Time: start.Add(time.Duration(i) * 500 * time.Millisecond), PNG: pngFrame(t, c)})
+	}
+	return s
+}
+
+func ScreencastDedupTest(t *testing.T) {
+	s := testScreencast(t)
+	frames := s.Frames()
+	if len(frames) != 3 {
+		t.Fatalf("expected 3 frames, got %d", len(frames))
+	}
+	if d := frames[1].Time.Sub(frames[0].Time); d != time.Second {
+		t.Fatalf("expected the duplicate to be dropped, got a %v gap", d)
+	}
+
+	s = NewScreencast(client).MaxFrames(2)
+	s.add(ScreencastFrame{PNG: pngFrame(t, color.White)})
+	s.add(ScreencastFrame{PNG: pngFrame(t, color.Black)})
+	s.add(ScreencastFrame{PNG: pngFrame(t, color.White)})
+	if frames = s.Frames(); len(frames) != 2 || !bytes.Equal(frames[1].PNG, pngFrame(t, color.White)) {
+		t.Fatalf("expected the last 2 frames, got %d", len(frames))
+	}
+}
+
+func ScreencastGIFTest(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testScreencast(t).WriteGIF(&buf); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) != 3 {
+		t.Fatalf("expected 3 images, got %d", len(anim.Image))
+	}
+	if anim.Delay[0] != 100 || anim.Delay[1] != 100 || anim.Delay[2] != 100 {
+		t.Fatalf("unexpected delays %v", anim.Delay)
+	}
+	if anim.Config.Width != 4 || anim.Config.Height != 3 {
+		t.Fatalf("unexpected size %dx%d", anim.Config.Width, anim.Config.Height)
+	}
+
+	if err = NewScreencast(client).WriteGIF(&buf); err == nil {
+		t.Fatal("expected an error without frames")
+	}
+}
+
+func ScreencastPNGsTest(t *testing.T) {
+	dir := t.TempDir()
+	if err := testScreencast(t).WritePNGs(dir); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	expected := []string{"0000-000000000ms.png", "0001-000001000ms.png", "0002-000002000ms.png"}
+	if len(names) != len(expected) {
+		t.Fatalf("unexpected files %v", names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Fatalf("unexpected files %v", names)
+		}
+	}
+}
+
+// required test in sequential main client test: client_test.go
+func ScreencastTest(t *testing.T) {
+	cast := NewScreencast(client).AfterCommands()
+	if err := cast.Start(); err != nil {
+		t.Fatalf("%#v", err)
+	}
+	navigateLocal("table.html")
+	navigateLocal("form.html")
+	if err := cast.Stop(); err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	frames := cast.Frames()
+	if len(frames) < 2 {
+		t.Fatalf("expected frames after navigations, got %d", len(frames))
+	}
+	if frames[len(frames)-1].Command != "WebDriver:Navigate" {
+		t.Fatalf("unexpected command %q", frames[len(frames)-1].Command)
+	}
+
+	var buf bytes.Buffer
+	if err := cast.WriteGIF(&buf); err != nil {
+		t.Fatal(err)
+	}
+}
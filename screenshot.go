@@ -0,0 +1,102 @@
+package marionette
+
+import (
+	"bytes"
+	"encoding/hex"
+	"image"
+	"image/png"
+)
+
+// ScreenshotOptions are the parameters of a screenshot. The zero value
+// captures the viewport.
+type ScreenshotOptions struct {
+	// Full captures the whole document instead of the viewport. It is
+	// ignored for element screenshots.
+	Full bool
+	// Scroll scrolls the element into view before an element screenshot.
+	Scroll bool
+	// Highlights are elements outlined in red in the screenshot.
+	Highlights []*WebElement
+	// Hash returns the SHA-256 of the base64-encoded PNG image instead of its
+	// data, to compare screenshots without transferring them.
+	Hash bool
+
+	element string
+}
+
+// defaultScreenshotOptions are the options of Screenshot, matching the
+// defaults of Marionette.
+var defaultScreenshotOptions = ScreenshotOptions{Full: true, Scroll: true}
+
+func (o ScreenshotOptions) params() map[string]any {
+	params := map[string]any{
+		"full":   o.Full,
+		"scroll": o.Scroll,
+		"hash":   o.Hash,
+	}
+	if o.element != "" {
+		params["id"] = o.element
+	}
+	if len(o.Highlights) != 0 {
+		ids := make([]string, len(o.Highlights))
+		for i, e := range o.Highlights {
+			ids[i] = e.id
+		}
+		params["highlights"] = ids
+	}
+	return params
+}
+
+func (c *Client) takeScreenshotWith(opts ScreenshotOptions) ([]byte, error) {
+	for _, e := range opts.Highlights {
+		if _, err := e.locatedId(); err != nil {
+			return nil, err
+		}
+	}
+	params := opts.params()
+	if opts.Hash {
+		var out struct {
+			Value string `json:"value"`
+		}
+		if err := c.tr.SendAndDecode(&out, "WebDriver:TakeScreenshot", params); err != nil {
+			return nil, err
+		}
+		return hex.DecodeString(out.Value)
+	}
+
+	var out struct {
+		Value []byte `json:"value"`
+	}
+	err := c.tr.SendAndDecode(&out, "WebDriver:TakeScreenshot", params)
+	return out.Value, err
+}
+
+// ScreenshotWith takes a screenshot of the page with the given options. It
+// returns PNG data, or a hash of the image with opts.Hash.
+func (c *Client) ScreenshotWith(opts ScreenshotOptions) ([]byte, error) {
+	opts.element = ""
+	return c.takeScreenshotWith(opts)
+}
+
+// ScreenshotImageWith takes a screenshot of the page with the given options.
+// opts.Hash is ignored.
+func (c *Client) ScreenshotImageWith(opts ScreenshotOptions) (image.Image, error) {
+	opts.element = ""
+	opts.Hash = false
+	data, err := c.takeScreenshotWith(opts)
+	if err != nil {
+		return nil, err
+	}
+	return png.Decode(bytes.NewReader(data))
+}
+
+// ScreenshotWith takes a screenshot of the element with the given options.
+// It returns PNG data, or a hash of the image with opts.Hash.
+func (e *WebElement) ScreenshotWith(opts ScreenshotOptions) (data []byte, err error) {
+	err = e.heal(func() (err error) {
+		opts.element = e.id
+		data, err = e.c.takeScreenshotWith(opts)
+		return
+	})
+	return
+}
@@ -0,0 +1,68 @@
+package marionette
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"reflect"
+	"testing"
+)
+
+func TestScreenshotOptions(t *testing.T) {
+	t.Run("ScreenshotParamsTest", ScreenshotParamsTest)
+}
+
+func ScreenshotParamsTest(t *testing.T) {
+	params := defaultScreenshotOptions.params()
+	expected := map[string]any{"full": true, "scroll": true, "hash": false}
+	if !reflect.DeepEqual(params, expected) {
+		t.Fatalf("unexpected params %v", params)
+	}
+
+	opts := ScreenshotOptions{
+		Hash:       true,
+		Highlights: []*WebElement{{id: "a"}, {id: "b"}},
+		element:    "e",
+	}
+	params = opts.params()
+	expected = map[string]any{
+		"full": false, "scroll": false, "hash": true,
+		"id": "e", "highlights": []string{"a", "b"},
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Fatalf("unexpected params %v", params)
+	}
+}
+
+// required test in sequential main client test: client_test.go
+func ScreenshotOptionsTest(t *testing.T) {
+	navigateLocal("table.html")
+
+	viewport, err := client.ScreenshotWith(ScreenshotOptions{})
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	hash, err := client.ScreenshotWith(ScreenshotOptions{Hash: true})
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	// Marionette hashes the base64 encoding of the image
+	if sum := sha256.Sum256([]byte(base64.StdEncoding.EncodeToString(viewport))); string(hash) != string(sum[:]) {
+		t.Fatalf("hash %x doesn't match the image %x", hash, sum)
+	}
+
+	table, err := client.FindElement(By(CSS_SELECTOR), "table")
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	highlighted, err := client.ScreenshotImageWith(ScreenshotOptions{Full: true, Highlights: []*WebElement{table}})
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if highlighted.Bounds().Empty() {
+		t.Fatal("empty screenshot")
+	}
+
+	if _, err = table.ScreenshotWith(ScreenshotOptions{Scroll: true}); err != nil {
+		t.Fatalf("%#v", err)
+	}
+}
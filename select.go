@@ -0,0 +1,177 @@
+package marionette
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Select wraps a <select> element to list and choose its options.
+type Select struct {
+	e        *WebElement
+	multiple bool
+}
+
+// NewSelect wraps e, which must be a <select> element.
+func NewSelect(e *WebElement) (*Select, error) {
+	tag, err := Property[string](e, "localName")
+	if err != nil {
+		return nil, err
+	}
+	if tag != "select" {
+		return nil, fmt.Errorf("element is a <%s>, not a <select>", tag)
+	}
+	multiple, err := Property[bool](e, "multiple")
+	if err != nil {
+		return nil, err
+	}
+	return &Select{e: e, multiple: multiple}, nil
+}
+
+// Element returns the wrapped <select> element.
+func (s *Select) Element() *WebElement {
+	return s.e
+}
+
+// Multiple tells if several options can be selected.
+func (s *Select) Multiple() bool {
+	return s.multiple
+}
+
+// Options returns all the options, in document order.
+func (s *Select) Options() ([]*WebElement, error) {
+	return s.e.FindElements(TAG_NAME, "option")
+}
+
+// SelectedOptions returns the selected options.
+func (s *Select) SelectedOptions() ([]*WebElement, error) {
+	options, err := s.Options()
+	if err != nil {
+		return nil, err
+	}
+	var selected []*WebElement
+	for _, o := range options {
+		if o.Selected() {
+			selected = append(selected, o)
+		}
+	}
+	return selected, nil
+}
+
+// SelectByText selects the options whose visible text is text, ignoring
+// leading, trailing and repeated whitespaces. Only the first one is selected
+// if the select is not multiple.
+func (s *Select) SelectByText(text string) error {
+	text = normalizeSpaces(text)
+	return s.setSelected(true, fmt.Sprintf("text %q", text), func(_ int, o *WebElement) (bool, error) {
+		return normalizeSpaces(o.Text()) == text, nil
+	})
+}
+
+// SelectByValue selects the options whose value is value. Only the first one
+// is selected if the select is not multiple.
+func (s *Select) SelectByValue(value string) error {
+	return s.setSelected(true, fmt.Sprintf("value %q", value), func(_ int, o *WebElement) (bool, error) {
+		v, err := Property[string](o, "value")
+		return v == value, err
+	})
+}
+
+// SelectByIndex selects the option at index.
+func (s *Select) SelectByIndex(index int) error {
+	return s.setSelected(true, fmt.Sprintf("index %d", index), func(i int, _ *WebElement) (bool, error) {
+		return i == index, nil
+	})
+}
+
+// DeselectByText deselects the options whose visible text is text. The
+// select must be multiple.
+func (s *Select) DeselectByText(text string) error {
+	text = normalizeSpaces(text)
+	return s.setSelected(false, fmt.Sprintf("text %q", text), func(_ int, o *WebElement) (bool, error) {
+		return normalizeSpaces(o.Text()) == text, nil
+	})
+}
+
+// DeselectByValue deselects the options whose value is value. The select
+// must be multiple.
+func (s *Select) DeselectByValue(value string) error {
+	return s.setSelected(false, fmt.Sprintf("value %q", value), func(_ int, o *WebElement) (bool, error) {
+		v, err := Property[string](o, "value")
+		return v == value, err
+	})
+}
+
+// DeselectByIndex deselects the option at index. The select must be
+// multiple.
+func (s *Select) DeselectByIndex(index int) error {
+	return s.setSelected(false, fmt.Sprintf("index %d", index), func(i int, _ *WebElement) (bool, error) {
+		return i == index, nil
+	})
+}
+
+// DeselectAll deselects all the options. The select must be multiple.
+func (s *Select) DeselectAll() error {
+	if !s.multiple {
+		return errors.New("can't deselect options of a non-multiple select")
+	}
+	options, err := s.SelectedOptions()
+	if err != nil {
+		return err
+	}
+	for _, o := range options {
+		if err = o.Click(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Select) setSelected(selected bool, desc string, match func(i int, o *WebElement) (bool, error)) error {
+	if !selected && !s.multiple {
+		return errors.New("can't deselect options of a non-multiple select")
+	}
+
+	options, err := s.Options()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for i, o := range options {
+		ok, err := match(i, o)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		found = true
+
+		if !o.Enabled() {
+			return fmt.Errorf("option with %s is disabled", desc)
+		}
+		if o.Selected() != selected {
+			if err = o.Click(); err != nil {
+				return err
+			}
+		}
+		if !s.multiple {
+			return nil
+		}
+	}
+
+	if !found {
+		return &DriverError{
+			ErrorType: ERROR_NO_SUCH_ELEMENT,
+			Message:   "cannot locate option with " + desc,
+		}
+	}
+	return nil
+}
+
+// normalizeSpaces trims s and replaces its whitespace sequences by a single
+// space.
+func normalizeSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
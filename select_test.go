@@ -0,0 +1,95 @@
+package marionette
+
+import "testing"
+
+func selectedValues(t *testing.T, s *Select) (values []string) {
+	options, err := s.SelectedOptions()
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	for _, o := range options {
+		v, _ := o.Attribute("value")
+		values = append(values, v)
+	}
+	return
+}
+
+// required test in sequential main client test: client_test.go
+func SelectTest(t *testing.T) {
+	navigateLocal("form.html")
+
+	e, err := client.FindElement(By(NAME), "selectomatic")
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	s, err := NewSelect(e)
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if s.Multiple() {
+		t.Fatal("selectomatic is not multiple")
+	}
+
+	if err = s.SelectByText("  Still learning how to count,   apparently "); err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if v := selectedValues(t, s); len(v) != 1 || v[0] != "still learning how to count, apparently" {
+		t.Fatalf("unexpected selection %v", v)
+	}
+	if err = s.SelectByIndex(1); err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if v := selectedValues(t, s); len(v) != 1 || v[0] != "two" {
+		t.Fatalf("unexpected selection %v", v)
+	}
+	if err = s.SelectByValue("five"); !IsDriverError(err, ERROR_NO_SUCH_ELEMENT) {
+		t.Fatalf("expected no such element, got %#v", err)
+	}
+	if err = s.DeselectAll(); err == nil {
+		t.Fatal("expected an error deselecting a non-multiple select")
+	}
+
+	e, err = client.FindElement(By(ID), "multi")
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	multi, err := NewSelect(e)
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if err = multi.SelectByValue("ham"); err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if err = multi.DeselectByText("Eggs"); err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if v := selectedValues(t, multi); len(v) != 2 || v[0] != "ham" || v[1] != "sausages" {
+		t.Fatalf("unexpected selection %v", v)
+	}
+	if err = multi.DeselectAll(); err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if v := selectedValues(t, multi); len(v) != 0 {
+		t.Fatalf("unexpected selection %v", v)
+	}
+
+	e, err = client.FindElement(By(NAME), "no-select")
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	disabled, err := NewSelect(e)
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if err = disabled.SelectByValue("foo"); err == nil {
+		t.Fatal("expected an error selecting a disabled option")
+	}
+
+	e, err = client.FindElement(By(ID), "email")
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if _, err = NewSelect(e); err == nil {
+		t.Fatal("expected an error wrapping an input")
+	}
+}
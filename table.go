@@ -0,0 +1,156 @@
+package marionette
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Table is the content of an HTML table.
+type Table struct {
+	// Headers are the texts of the header rows (the thead rows, or the
+	// leading rows made of th cells only). Multiple header rows are joined
+	// with a space per column.
+	Headers []string
+	// Rows are the texts of the other rows. Cells spanning several rows or
+	// columns are repeated in each of them, so all the rows have the same
+	// number of cells.
+	Rows [][]string
+}
+
+// Table reads the content of a table element in a single script execution.
+func (e *WebElement) Table() (*Table, error) {
+	r, err := executeScriptFrom(e, tableScript)
+	if err != nil {
+		return nil, err
+	}
+	var out struct {
+		Value *Table `json:"value"`
+	}
+	if err = json.Unmarshal([]byte(r.Value), &out); err != nil {
+		return nil, err
+	}
+	return out.Value, nil
+}
+
+// Decode fills the slice of structs pointed by dest with one item per row.
+//
+// Struct fields match the column whose header is the field's "table" tag,
+// or the field name, ignoring case and spaces. Values are converted as in
+// Extract, with the optional layout tag for time.Time fields. Columns
+// without field are ignored.
+//
+//	var users []struct {
+//		ID   int
+//		Name string `table:"Full name"`
+//	}
+//	err = table.Decode(&users)
+func (t *Table) Decode(dest any) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Slice || v.Elem().Type().Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("dest must be a pointer to a slice of structs, not %T", dest)
+	}
+	typ := v.Elem().Type().Elem()
+
+	normalize := func(s string) string {
+		return strings.ToLower(strings.Join(strings.Fields(s), ""))
+	}
+	columns := map[string]int{}
+	for i, h := range t.Headers {
+		if _, dup := columns[normalize(h)]; !dup {
+			columns[normalize(h)] = i
+		}
+	}
+
+	type column struct {
+		field  int
+		cell   int
+		layout string
+		tag    string
+	}
+	var cols []column
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, ok := sf.Tag.Lookup("table")
+		if !ok {
+			name = sf.Name
+		}
+		if cell, ok := columns[normalize(name)]; ok {
+			cols = append(cols, column{field: i, cell: cell, layout: sf.Tag.Get("layout"), tag: name})
+		}
+	}
+
+	slice := reflect.MakeSlice(v.Elem().Type(), len(t.Rows), len(t.Rows))
+	var errs []*PageFieldError
+	for r, row := range t.Rows {
+		item := slice.Index(r)
+		for _, col := range cols {
+			if col.cell >= len(row) {
+				continue
+			}
+			err := setString(item.Field(col.field), row[col.cell], col.layout)
+			if err != nil {
+				errs = append(errs, &PageFieldError{
+					Field: fmt.Sprintf("[%d].%s", r, typ.Field(col.field).Name),
+					Tag:   "table=" + col.tag,
+					Err:   err,
+				})
+			}
+		}
+	}
+	v.Elem().Set(slice)
+
+	if len(errs) != 0 {
+		return &PageError{Fields: errs}
+	}
+	return nil
+}
+
+// tableScript reads a table in a grid, expanding spanned cells.
+const tableScript = `
+let table = arguments[0];
+let rows = Array.from(table.rows);
+
+let grid = rows.map(() => []);
+rows.forEach((row, y) => {
+	let x = 0;
+	for (let cell of row.cells) {
+		while (grid[y][x] !== undefined) x++;
+		let text = (cell.innerText ?? cell.textContent).trim();
+		let rowSpan = cell.rowSpan || (rows.length - y);
+		for (let dy = 0; dy < rowSpan && y + dy < rows.length; dy++) {
+			for (let dx = 0; dx < cell.colSpan; dx++) {
+				grid[y + dy][x + dx] = text;
+			}
+		}
+		x += cell.colSpan;
+	}
+});
+
+let width = Math.max(0, ...grid.map((r) => r.length));
+grid = grid.map((r) => Array.from({length: width}, (_, i) => r[i] ?? ""));
+
+let headerCount = rows.filter((row) => row.parentNode.localName == "thead").length;
+if (headerCount == 0) {
+	while (headerCount < rows.length && rows[headerCount].cells.length &&
+		Array.from(rows[headerCount].cells).every((c) => c.localName == "th")) {
+		headerCount++;
+	}
+}
+
+let headers = Array.from({length: width}, (_, x) => {
+	let texts = [];
+	for (let y = 0; y < headerCount; y++) {
+		let t = grid[y][x];
+		if (t && texts[texts.length - 1] != t) texts.push(t);
+	}
+	return texts.join(" ");
+});
+
+// table.rows lists the thead rows first
+return {headers, rows: grid.slice(headerCount)};
+`
@@ -0,0 +1,86 @@
+package marionette
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTable(t *testing.T) {
+	t.Run("TableDecodeTest", TableDecodeTest)
+}
+
+type tablePlayer struct {
+	Team  string
+	Name  string `table:"Player Name"`
+	Goals int    `table:"player goals"`
+}
+
+func TableDecodeTest(t *testing.T) {
+	table := &Table{
+		Headers: []string{"Team", "Player Name", "Player Goals"},
+		Rows: [][]string{
+			{"Blue", "Alice", "3"},
+			{"Blue", "Bob", "1"},
+			{"Red", "none", "none"},
+		},
+	}
+
+	var players []tablePlayer
+	err := table.Decode(&players)
+
+	var pe *PageError
+	if !errors.As(err, &pe) || len(pe.Fields) != 1 || pe.Fields[0].Field != "[2].Goals" {
+		t.Fatalf("expected an error on [2].Goals, got %v", err)
+	}
+
+	expected := []tablePlayer{{"Blue", "Alice", 3}, {"Blue", "Bob", 1}, {"Red", "none", 0}}
+	if !reflect.DeepEqual(players, expected) {
+		t.Fatalf("expected %v, got %v", expected, players)
+	}
+
+	if err = table.Decode(players); err == nil {
+		t.Fatal("expected an error for a non-pointer destination")
+	}
+}
+
+// required test in sequential main client test: client_test.go
+func TableTest(t *testing.T) {
+	navigateLocal("table.html")
+	e, err := client.FindElement(By(ID), "the-table")
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	table, err := e.Table()
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	expected := &Table{
+		Headers: []string{"ID", "Name"},
+		Rows:    [][]string{{"1", "John Doe"}, {"2", "Alice"}},
+	}
+	if !reflect.DeepEqual(table, expected) {
+		t.Fatalf("expected %v, got %v", expected, table)
+	}
+
+	navigateLocal("table_spans.html")
+	e, err = client.FindElement(By(ID), "spans")
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	table, err = e.Table()
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	expected = &Table{
+		Headers: []string{"Team", "Player Name", "Player Goals"},
+		Rows: [][]string{
+			{"Blue", "Alice", "3"},
+			{"Blue", "Bob", "1"},
+			{"Red", "none", "none"},
+		},
+	}
+	if !reflect.DeepEqual(table, expected) {
+		t.Fatalf("expected %v, got %v", expected, table)
+	}
+}
@@ -0,0 +1,362 @@
+package marionette
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxTraceResult is the size above which command results are truncated in
+// traces.
+const maxTraceResult = 8 << 10
+
+// TraceEvent is a command recorded by a Tracer.
+type TraceEvent struct {
+	Command  string          `json:"command"`
+	Params   json.RawMessage `json:"params,omitempty"`
+	Start    time.Time       `json:"start"`
+	Duration time.Duration   `json:"duration"`
+	// Result is the command result, truncated to a few kilobytes.
+	Result string `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// URL is the URL of the current page when the command was sent.
+	URL string `json:"url,omitempty"`
+	// Before and After are PNG screenshots of the affected element, or of
+	// the viewport, taken around state changing commands.
+	Before []byte `json:"-"`
+	After  []byte `json:"-"`
+}
+
+// Tracer records the commands of a session, with screenshots around the
+// StateChangingCommands, for post-mortem debugging. The trace is written as
+// a zip archive with an HTML viewer by WriteZip.
+//
+//	tracer := NewTracer(client)
+//	tracer.Start()
+//	defer tracer.WriteZipFile("trace.zip")
+//	defer tracer.Stop()
+type Tracer struct {
+	c           *Client
+	screenshots bool
+
+	mu         sync.Mutex
+	events     []TraceEvent
+	url        string
+	removeHook func()
+}
+
+// traceURLChangingCommands are the commands that may change the URL of the
+// current page, after which a Tracer requests it.
+var traceURLChangingCommands = map[string]bool{
+	"WebDriver:Back":            true,
+	"WebDriver:Forward":         true,
+	"WebDriver:ElementClick":    true,
+	"WebDriver:ElementSendKeys": true,
+	"WebDriver:PerformActions":  true,
+	"WebDriver:NewWindow":       true,
+	"WebDriver:CloseWindow":     true,
+	"WebDriver:SwitchToWindow":  true,
+}
+
+// NewTracer returns a Tracer of the session of c. It records nothing until
+// started.
+func NewTracer(c *Client) *Tracer {
+	return &Tracer{c: c, screenshots: true}
+}
+
+// Screenshots enables or disables the screenshots around commands, enabled
+// by default.
+func (t *Tracer) Screenshots(enabled bool) *Tracer {
+	t.screenshots = enabled
+	return t
+}
+
+// Start starts recording the commands.
+func (t *Tracer) Start() {
+	if t.removeHook != nil {
+		return
+	}
+	t.removeHook = t.c.tr.addHook(&commandHook{before: t.before, after: t.after})
+}
+
+// Stop stops recording the commands.
+func (t *Tracer) Stop() {
+	if t.removeHook != nil {
+		t.removeHook()
+		t.removeHook = nil
+	}
+}
+
+// Events returns the recorded commands.
+func (t *Tracer) Events() []TraceEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]TraceEvent(nil), t.events...)
+}
+
+func (t *Tracer) before(command string, values any) any {
+	ev := &TraceEvent{Command: command}
+	if values != nil {
+		ev.Params, _ = json.Marshal(values)
+	}
+
+	t.mu.Lock()
+	ev.URL = t.url
+	t.mu.Unlock()
+
+	if t.screenshots && StateChangingCommands[command] {
+		ev.Before, _ = t.c.captureElement(traceElement(command, ev.Params))
+	}
+
+	ev.Start = time.Now()
+	return ev
+}
+
+func (t *Tracer) after(command string, _ any, r *Response, err error, elapsed time.Duration, state any) {
+	ev := state.(*TraceEvent)
+	ev.Duration = elapsed
+	if err != nil {
+		ev.Error = err.Error()
+	} else if r != nil {
+		ev.Result = r.Value
+		if len(ev.Result) > maxTraceResult {
+			ev.Result = ev.Result[:maxTraceResult] + "..."
+		}
+	}
+
+	if t.screenshots && StateChangingCommands[command] && command != "WebDriver:DeleteSession" {
+		ev.After, _ = t.c.captureElement(traceElement(command, ev.Params))
+	}
+
+	var url string
+	refresh := err == nil && traceURLChangingCommands[command]
+	if refresh {
+		url, _ = t.c.currentURL()
+	}
+
+	t.mu.Lock()
+	t.events = append(t.events, *ev)
+	if refresh {
+		t.url = url
+	} else if err == nil {
+		t.url = nextTraceURL(t.url, command, ev.Params, ev.Result)
+	}
+	t.mu.Unlock()
+}
+
+// currentURL returns the URL of the current page without calling the
+// transport hooks.
+func (c *Client) currentURL() (string, error) {
+	r, err := c.tr.send("WebDriver:GetCurrentURL", nil)
+	if err != nil {
+		return "", err
+	}
+	var out struct {
+		Value string `json:"value"`
+	}
+	err = json.Unmarshal([]byte(r.Value), &out)
+	return out.Value, err
+}
+
+// nextTraceURL returns the URL of the current page after a successful
+// command outside traceURLChangingCommands, url being the one before it.
+func nextTraceURL(url, command string, params json.RawMessage, result string) string {
+	switch {
+	case command == "WebDriver:Navigate":
+		var p struct {
+			URL string `json:"url"`
+		}
+		json.Unmarshal(params, &p)
+		return p.URL
+	case command == "WebDriver:GetCurrentURL":
+		var out struct {
+			Value string `json:"value"`
+		}
+		json.Unmarshal([]byte(result), &out)
+		return out.Value
+	case command == "WebDriver:DeleteSession":
+		return ""
+	}
+	return url
+}
+
+// traceElement returns the element affected by a command, if any.
+func traceElement(command string, params json.RawMessage) string {
+	if !strings.HasPrefix(command, "WebDriver:Element") || params == nil {
+		return ""
+	}
+	var p struct {
+		Id string `json:"id"`
+	}
+	json.Unmarshal(params, &p)
+	return p.Id
+}
+
+// captureElement takes a PNG screenshot of an element without scrolling,
+// or of the viewport if id is empty or the element is gone, without calling
+// the transport hooks.
+func (c *Client) captureElement(id string) ([]byte, error) {
+	if id == "" {
+		return c.captureViewport()
+	}
+	r, err := c.tr.send("WebDriver:TakeScreenshot", ScreenshotOptions{element: id}.params())
+	if err != nil {
+		return c.captureViewport()
+	}
+	var out struct {
+		Value []byte `json:"value"`
+	}
+	err = json.Unmarshal([]byte(r.Value), &out)
+	return out.Value, err
+}
+
+// traceArchiveEvent is a TraceEvent in the archive, with the names of its
+// screenshots.
+type traceArchiveEvent struct {
+	TraceEvent
+	DurationMs float64 `json:"durationMs"`
+	Before     string  `json:"before,omitempty"`
+	After      string  `json:"after,omitempty"`
+}
+
+// WriteZip writes the trace as a zip archive containing trace.json, the
+// screenshots and index.html, a viewer that works from the extracted files
+// without any server.
+func (t *Tracer) WriteZip(w io.Writer) error {
+	events := t.Events()
+	z := zip.NewWriter(w)
+
+	writeFile := func(name string, data []byte) error {
+		f, err := z.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		return err
+	}
+
+	archived := make([]traceArchiveEvent, len(events))
+	for i, ev := range events {
+		a := traceArchiveEvent{TraceEvent: ev, DurationMs: float64(ev.Duration.Microseconds()) / 1000}
+		if ev.Before != nil {
+			a.Before = fmt.Sprintf("screenshots/%04d-before.png", i)
+			if err := writeFile(a.Before, ev.Before); err != nil {
+				return err
+			}
+		}
+		if ev.After != nil {
+			a.After = fmt.Sprintf("screenshots/%04d-after.png", i)
+			if err := writeFile(a.After, ev.After); err != nil {
+				return err
+			}
+		}
+		archived[i] = a
+	}
+
+	// json.Marshal escapes <, > and &, so the trace can be inlined in a script
+	data, err := json.Marshal(archived)
+	if err != nil {
+		return err
+	}
+	if err = writeFile("trace.json", data); err != nil {
+		return err
+	}
+	viewer := strings.Replace(traceViewer, "/*TRACE*/[]", string(data), 1)
+	if err = writeFile("index.html", []byte(viewer)); err != nil {
+		return err
+	}
+	return z.Close()
+}
+
+// WriteZipFile writes the trace archive to path.
+func (t *Tracer) WriteZipFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = t.WriteZip(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// traceViewer is the HTML viewer of trace archives.
+const traceViewer = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="UTF-8">
+<title>Marionette trace</title>
+<style>
+body { margin: 0; display: flex; height: 100vh; font: 13px sans-serif; }
+#events { width: 40%; overflow: auto; border-right: 1px solid #ccc; margin: 0; padding: 0; list-style: none; }
+#events li { padding: 4px 8px; cursor: pointer; border-bottom: 1px solid #eee; display: flex; gap: 8px; }
+#events li:hover { background: #f4f4f4; }
+#events li.selected { background: #dde8ff; }
+#events li.error .command { color: #c00; }
+#events .command { flex: 1; font-family: monospace; }
+#events .duration { color: #888; }
+#details { flex: 1; overflow: auto; padding: 8px 16px; }
+pre { background: #f8f8f8; padding: 8px; white-space: pre-wrap; word-break: break-all; }
+.error { color: #c00; }
+.shots { display: flex; gap: 16px; flex-wrap: wrap; }
+.shots img { max-width: 100%; border: 1px solid #ccc; }
+</style>
+</head>
+<body>
+<ul id="events"></ul>
+<div id="details"><p>Select a command.</p></div>
+<script>
+const TRACE = /*TRACE*/[];
+
+const list = document.getElementById("events");
+const details = document.getElementById("details");
+
+function el(tag, props, ...children) {
+	const e = Object.assign(document.createElement(tag), props);
+	e.append(...children);
+	return e;
+}
+
+function pretty(s) {
+	try { return JSON.stringify(JSON.parse(s), null, 2); } catch (e) { return s; }
+}
+
+function show(i) {
+	const ev = TRACE[i];
+	list.querySelectorAll("li").forEach((li, j) => li.classList.toggle("selected", i == j));
+	details.replaceChildren(
+		el("h2", {textContent: ev.command}),
+		el("p", {textContent: new Date(ev.start).toISOString() + " - " + ev.durationMs + " ms"}),
+		el("p", {textContent: "URL: " + (ev.url || "-")}),
+		el("h3", {textContent: "Parameters"}),
+		el("pre", {textContent: ev.params ? JSON.stringify(ev.params, null, 2) : "-"}),
+		el("h3", {textContent: ev.error ? "Error" : "Result"}),
+		el("pre", {textContent: ev.error || pretty(ev.result || "-"), className: ev.error ? "error" : ""}),
+	);
+	if (ev.before || ev.after) {
+		const shots = el("div", {className: "shots"});
+		for (const [label, src] of [["Before", ev.before], ["After", ev.after]]) {
+			if (src) shots.append(el("figure", {}, el("figcaption", {textContent: label}), el("img", {src})));
+		}
+		details.append(el("h3", {textContent: "Screenshots"}), shots);
+	}
+}
+
+TRACE.forEach((ev, i) => {
+	const li = el("li", {className: ev.error ? "error" : ""},
+		el("span", {className: "command", textContent: ev.command.replace(/^WebDriver:/, "")}),
+		el("span", {className: "duration", textContent: ev.durationMs + " ms"}));
+	li.onclick = () => show(i);
+	list.append(li);
+});
+if (TRACE.length) show(TRACE.length - 1);
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,145 @@
+package marionette
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrace(t *testing.T) {
+	t.Run("TraceElementTest", TraceElementTest)
+	t.Run("TraceZipTest", TraceZipTest)
+	t.Run("NextTraceURLTest", NextTraceURLTest)
+}
+
+func NextTraceURLTest(t *testing.T) {
+	const page = "http://example.com/"
+	for _, test := range []struct {
+		command  string
+		params   string
+		result   string
+		expected string
+	}{
+		{"WebDriver:Navigate", `{"url":"http://example.com/next"}`, "", "http://example.com/next"},
+		{"WebDriver:GetCurrentURL", "", `{"value":"http://example.com/current"}`, "http://example.com/current"},
+		{"WebDriver:DeleteSession", "", "", ""},
+		{"WebDriver:FindElement", `{"using":"id","value":"abc"}`, "", page},
+	} {
+		if url := nextTraceURL(page, test.command, json.RawMessage(test.params), test.result); url != test.expected {
+			t.Fatalf("%s: expected %q, got %q", test.command, test.expected, url)
+		}
+	}
+}
+
+func TraceElementTest(t *testing.T) {
+	params := json.RawMessage(`{"id":"abc","text":"hello"}`)
+	if id := traceElement("WebDriver:ElementSendKeys", params); id != "abc" {
+		t.Fatalf("unexpected element %q", id)
+	}
+	if id := traceElement("WebDriver:SwitchToFrame", json.RawMessage(`{"id":1}`)); id != "" {
+		t.Fatalf("unexpected element %q", id)
+	}
+	if id := traceElement("WebDriver:ElementClick", nil); id != "" {
+		t.Fatalf("unexpected element %q", id)
+	}
+}
+
+func readZip(t *testing.T, data []byte) map[string]string {
+	z, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	for _, f := range z.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(r)
+		r.Close()
+		files[f.Name] = string(b)
+	}
+	return files
+}
+
+func TraceZipTest(t *testing.T) {
+	tracer := NewTracer(client)
+	tracer.events = []TraceEvent{
+		{
+			Command:  "WebDriver:Navigate",
+			Params:   json.RawMessage(`{"url":"https://example.com/?a=<b>"}`),
+			Start:    time.Now(),
+			Duration: 1500 * time.Microsecond,
+			URL:      "about:blank",
+			Before:   []byte("before"),
+			After:    []byte("after"),
+		},
+		{Command: "WebDriver:FindElement", Error: "no such element: </script>"},
+	}
+
+	var buf bytes.Buffer
+	if err := tracer.WriteZip(&buf); err != nil {
+		t.Fatal(err)
+	}
+	files := readZip(t, buf.Bytes())
+
+	if files["screenshots/0000-before.png"] != "before" || files["screenshots/0000-after.png"] != "after" {
+		t.Fatal("missing screenshots")
+	}
+
+	var events []map[string]any
+	if err := json.Unmarshal([]byte(files["trace.json"]), &events); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0]["before"] != "screenshots/0000-before.png" || events[0]["durationMs"] != 1.5 {
+		t.Fatalf("unexpected trace %v", events)
+	}
+	if _, ok := events[1]["before"]; ok {
+		t.Fatal("unexpected screenshot")
+	}
+
+	index := files["index.html"]
+	if !strings.Contains(index, files["trace.json"]) {
+		t.Fatal("trace not inlined in the viewer")
+	}
+	if strings.Count(index, "</script>") != 1 {
+		t.Fatal("trace not escaped in the viewer")
+	}
+}
+
+// required test in sequential main client test: client_test.go
+func TraceTest(t *testing.T) {
+	tracer := NewTracer(client)
+	tracer.Start()
+	navigateLocal("form.html")
+	client.FindElement(By(ID), "does-not-exist")
+	if e, err := client.FindElement(By(ID), "checky"); err == nil {
+		e.Click()
+	}
+	// the URL is requested again after the click
+	client.FindElement(By(ID), "checky")
+	tracer.Stop()
+
+	events := tracer.Events()
+	if len(events) < 4 {
+		t.Fatalf("expected at least 4 events, got %d", len(events))
+	}
+	if events[0].Command != "WebDriver:Navigate" || events[0].Before == nil || events[0].After == nil {
+		t.Fatalf("unexpected first event %+v", events[0])
+	}
+	if events[1].Error == "" {
+		t.Fatal("expected an error on the missing element")
+	}
+	if events[len(events)-1].URL != localURL("form.html") {
+		t.Fatalf("unexpected URL %q", events[len(events)-1].URL)
+	}
+
+	var buf bytes.Buffer
+	if err := tracer.WriteZip(&buf); err != nil {
+		t.Fatal(err)
+	}
+}
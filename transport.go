@@ -8,6 +8,7 @@ import (
 	"log"
 	"net"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -17,6 +18,17 @@ type Transport struct {
 	messageID          int
 	conn               net.Conn
 	de                 Codec
+
+	mu     sync.Mutex // serializes commands
+	hookMu sync.Mutex
+	hooks  []*commandHook
+}
+
+// commandHook observes the commands sent through a Transport. The value
+// returned by before is given to after.
+type commandHook struct {
+	before func(command string, values any) any
+	after  func(command string, values any, r *Response, err error, elapsed time.Duration, state any)
 }
 
 type Response struct {
@@ -80,7 +92,57 @@ func (t *Transport) Close() error {
 	return err
 }
 
+// addHook registers h to be called around each command, until remove is
+// called. Commands sent by hooks through send are not observed.
+func (t *Transport) addHook(h *commandHook) (remove func()) {
+	t.hookMu.Lock()
+	defer t.hookMu.Unlock()
+	t.hooks = append(t.hooks[:len(t.hooks):len(t.hooks)], h)
+
+	return func() {
+		t.hookMu.Lock()
+		defer t.hookMu.Unlock()
+		hooks := make([]*commandHook, 0, len(t.hooks))
+		for _, o := range t.hooks {
+			if o != h {
+				hooks = append(hooks, o)
+			}
+		}
+		t.hooks = hooks
+	}
+}
+
 func (t *Transport) Send(command string, values any) (*Response, error) {
+	t.hookMu.Lock()
+	hooks := t.hooks
+	t.hookMu.Unlock()
+
+	if len(hooks) == 0 {
+		return t.send(command, values)
+	}
+
+	states := make([]any, len(hooks))
+	for i, h := range hooks {
+		if h.before != nil {
+			states[i] = h.before(command, values)
+		}
+	}
+	start := time.Now()
+	r, err := t.send(command, values)
+	elapsed := time.Since(start)
+	for i, h := range hooks {
+		if h.after != nil {
+			h.after(command, values, r, err, elapsed, states[i])
+		}
+	}
+	return r, err
+}
+
+// send sends a command without calling the hooks.
+func (t *Transport) send(command string, values any) (*Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	t.messageID++ // next message ID
 	buf, err := t.de.Encode(t.messageID, command, values)
 	if err != nil {
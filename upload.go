@@ -0,0 +1,113 @@
+package marionette
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileContent is an in-memory file to upload with UploadContents.
+type FileContent struct {
+	// Name is the base name of the file, as seen by the page.
+	Name string
+	Data []byte
+}
+
+// UploadFiles sets the files of an <input type="file">. The paths are
+// resolved to absolute paths and must exist; several paths require a
+// multiple input.
+//
+// Hidden file inputs, usually styled behind a button, are accepted unless
+// the session has the strictFileInteractability capability.
+func UploadFiles(e *WebElement, paths ...string) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("no file to upload")
+	}
+
+	abs := make([]string, len(paths))
+	for i, p := range paths {
+		var err error
+		if abs[i], err = filepath.Abs(p); err != nil {
+			return err
+		}
+		info, err := os.Stat(abs[i])
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return fmt.Errorf("%s is a directory", abs[i])
+		}
+		if strings.Contains(abs[i], "\n") {
+			return fmt.Errorf("file path %q contains a newline", abs[i])
+		}
+	}
+
+	typ, err := Property[string](e, "type")
+	if err != nil {
+		return err
+	}
+	if typ != "file" {
+		return fmt.Errorf("element is not a file input")
+	}
+	if len(abs) > 1 {
+		multiple, err := Property[bool](e, "multiple")
+		if err != nil {
+			return err
+		}
+		if !multiple {
+			return fmt.Errorf("can't upload %d files to a single file input", len(abs))
+		}
+	}
+
+	checks := []string{CHECK_ATTACHED, CHECK_ENABLED}
+	if e.c.Capabilities.StrictFileInteractability {
+		checks = inputChecks
+	}
+	if err = e.autoWait(checks); err != nil {
+		return err
+	}
+
+	// multiple files are separated by newlines
+	_, err = e.send("WebDriver:ElementSendKeys", map[string]any{"text": strings.Join(abs, "\n")})
+	return err
+}
+
+// UploadContents writes files to a temporary directory and uploads them with
+// UploadFiles.
+//
+// The browser reads the files when the page uses them, as when the form is
+// submitted, so cleanup must only be called afterwards. It removes the
+// temporary directory, and is also called when the upload fails.
+func UploadContents(e *WebElement, files ...FileContent) (cleanup func() error, err error) {
+	dir, err := os.MkdirTemp("", "marionette-upload-")
+	if err != nil {
+		return nil, err
+	}
+	cleanup = func() error { return os.RemoveAll(dir) }
+
+	paths := make([]string, len(files))
+	for i, f := range files {
+		name := filepath.Base(f.Name)
+		if name == "." || name == string(filepath.Separator) {
+			err = fmt.Errorf("invalid file name %q", f.Name)
+		} else {
+			// a sub-directory per file allows the same name twice
+			sub := filepath.Join(dir, fmt.Sprint(i))
+			paths[i] = filepath.Join(sub, name)
+			if err = os.Mkdir(sub, 0o700); err == nil {
+				err = os.WriteFile(paths[i], f.Data, 0o600)
+			}
+		}
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+	}
+
+	if err = UploadFiles(e, paths...); err != nil {
+		cleanup()
+		return nil, err
+	}
+	return cleanup, nil
+}
@@ -0,0 +1,90 @@
+package marionette
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUpload(t *testing.T) {
+	t.Run("UploadFilesValidationTest", UploadFilesValidationTest)
+	t.Run("UploadContentsValidationTest", UploadContentsValidationTest)
+}
+
+func UploadFilesValidationTest(t *testing.T) {
+	e := &WebElement{c: client, id: "unused"}
+
+	if err := UploadFiles(e); err == nil {
+		t.Fatal("expected an error without files")
+	}
+	if err := UploadFiles(e, filepath.Join(t.TempDir(), "missing.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected a not exist error, got %v", err)
+	}
+	if err := UploadFiles(e, t.TempDir()); err == nil {
+		t.Fatal("expected an error for a directory")
+	}
+}
+
+func UploadContentsValidationTest(t *testing.T) {
+	e := &WebElement{c: client, id: "unused"}
+
+	cleanup, err := UploadContents(e, FileContent{Name: "/", Data: []byte("x")})
+	if err == nil || cleanup != nil {
+		t.Fatal("expected an error for an invalid name")
+	}
+}
+
+// required test in sequential main client test: client_test.go
+func UploadFilesTest(t *testing.T) {
+	navigateLocal("upload.html")
+
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	os.WriteFile(a, []byte("a"), 0o600)
+	os.WriteFile(b, []byte("b"), 0o600)
+
+	fileNames := func(id string) []string {
+		r, err := executeScriptFrom(findElement(t, By(ID), id), "return Array.from(arguments[0].files, f => f.name);")
+		if err != nil {
+			t.Fatalf("%#v", err)
+		}
+		var out struct {
+			Value []string `json:"value"`
+		}
+		if err = json.Unmarshal([]byte(r.Value), &out); err != nil {
+			t.Fatal(err)
+		}
+		return out.Value
+	}
+
+	if err := UploadFiles(findElement(t, By(ID), "single"), a); err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if n := fileNames("single"); len(n) != 1 || n[0] != "a.txt" {
+		t.Fatalf("unexpected files %v", n)
+	}
+
+	if err := UploadFiles(findElement(t, By(ID), "single"), a, b); err == nil {
+		t.Fatal("expected an error for several files in a single input")
+	}
+
+	if err := UploadFiles(findElement(t, By(ID), "several"), a, b); err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if n := fileNames("several"); len(n) != 2 || n[0] != "a.txt" || n[1] != "b.txt" {
+		t.Fatalf("unexpected files %v", n)
+	}
+
+	cleanup, err := UploadContents(findElement(t, By(ID), "hidden"), FileContent{Name: "report.csv", Data: []byte("a,b\n")})
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if n := fileNames("hidden"); len(n) != 1 || n[0] != "report.csv" {
+		t.Fatalf("unexpected files %v", n)
+	}
+	if err = cleanup(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,282 @@
+// Package visual compares screenshots with golden images, for visual
+// regression tests.
+//
+// Pixels are compared in the YIQ color space, and anti-aliased pixels are
+// detected to tolerate rendering differences, following the pixelmatch
+// algorithm (https://github.com/mapbox/pixelmatch).
+package visual
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/mcluseau/marionette"
+)
+
+// maxYIQDelta is the squared YIQ distance between black and white.
+const maxYIQDelta = 35215
+
+// Colors of the diff image.
+var (
+	DiffColor        = color.NRGBA{R: 255, A: 255}
+	AntiAliasedColor = color.NRGBA{R: 255, G: 255, A: 255}
+	IgnoredColor     = color.NRGBA{B: 255, A: 64}
+)
+
+// Options tune the comparison of images.
+type Options struct {
+	// Tolerance is the color distance, from 0 to 1, under which pixels are
+	// considered equal. 0 requires identical colors, 0.1 is a good start.
+	Tolerance float64
+	// MaxDiffPixels is the number of different pixels allowed.
+	MaxDiffPixels int
+	// StrictAntiAliasing counts anti-aliased pixels as differences instead
+	// of ignoring them.
+	StrictAntiAliasing bool
+	// Ignore are regions of the images that are not compared, in image
+	// coordinates.
+	Ignore []image.Rectangle
+	// IgnoreElements are elements whose region is not compared. They are
+	// only used by MatchPage and MatchElement, as Compare knows nothing of
+	// pages.
+	IgnoreElements []*marionette.WebElement
+}
+
+// Result is the outcome of a comparison.
+type Result struct {
+	// DiffPixels is the number of different pixels.
+	DiffPixels int
+	// AntiAliasedPixels is the number of different pixels ignored as
+	// anti-aliasing.
+	AntiAliasedPixels int
+	// Diff shows the compared image faded, with DiffColor on different pixels,
+	// AntiAliasedColor on anti-aliased ones and IgnoredColor over ignored
+	// regions.
+	Diff *image.NRGBA
+
+	maxDiff int
+}
+
+// Ok returns true when the images match within the allowed differences.
+func (r *Result) Ok() bool {
+	return r.DiffPixels <= r.maxDiff
+}
+
+func (r *Result) String() string {
+	return fmt.Sprintf("%d different pixels (%d allowed), %d anti-aliased",
+		r.DiffPixels, r.maxDiff, r.AntiAliasedPixels)
+}
+
+// SizeError is returned when the compared images don't have the same size.
+type SizeError struct {
+	Got, Want image.Point
+}
+
+func (e *SizeError) Error() string {
+	return fmt.Sprintf("image size is %dx%d, expected %dx%d", e.Got.X, e.Got.Y, e.Want.X, e.Want.Y)
+}
+
+// Compare compares got with the expected image want.
+func Compare(got, want image.Image, opts Options) (*Result, error) {
+	if got.Bounds().Size() != want.Bounds().Size() {
+		return nil, &SizeError{Got: got.Bounds().Size(), Want: want.Bounds().Size()}
+	}
+
+	img1, img2 := toNRGBA(got), toNRGBA(want)
+	w, h := img1.Rect.Dx(), img1.Rect.Dy()
+
+	res := &Result{
+		Diff:    image.NewNRGBA(img1.Rect),
+		maxDiff: opts.MaxDiffPixels,
+	}
+	maxDelta := maxYIQDelta * opts.Tolerance * opts.Tolerance
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if ignored(opts.Ignore, x, y) {
+				drawGray(res.Diff, img1, x, y)
+				res.Diff.SetNRGBA(x, y, blendOver(res.Diff.NRGBAAt(x, y), IgnoredColor))
+				continue
+			}
+
+			delta := colorDelta(img1, img2, x, y, x, y, false)
+			if math.Abs(delta) <= maxDelta {
+				drawGray(res.Diff, img1, x, y)
+				continue
+			}
+
+			if !opts.StrictAntiAliasing && (antiAliased(img1, img2, x, y) || antiAliased(img2, img1, x, y)) {
+				res.AntiAliasedPixels++
+				res.Diff.SetNRGBA(x, y, AntiAliasedColor)
+			} else {
+				res.DiffPixels++
+				res.Diff.SetNRGBA(x, y, DiffColor)
+			}
+		}
+	}
+	return res, nil
+}
+
+// toNRGBA converts img to an NRGBA image with its origin at (0, 0).
+func toNRGBA(img image.Image) *image.NRGBA {
+	if n, ok := img.(*image.NRGBA); ok && n.Rect.Min == (image.Point{}) {
+		return n
+	}
+	b := img.Bounds()
+	n := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(n, n.Rect, img, b.Min, draw.Src)
+	return n
+}
+
+func ignored(regions []image.Rectangle, x, y int) bool {
+	p := image.Pt(x, y)
+	for _, r := range regions {
+		if p.In(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// antiAliased returns true if the pixel at x, y of img looks anti-aliased: it
+// has both darker and brighter neighbours, one of them being part of a flat
+// area in both images.
+func antiAliased(img, other *image.NRGBA, x, y int) bool {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	x0, y0 := imax(x-1, 0), imax(y-1, 0)
+	x2, y2 := imin(x+1, w-1), imin(y+1, h-1)
+
+	zeroes := 0
+	if x == x0 || x == x2 || y == y0 || y == y2 {
+		zeroes = 1
+	}
+
+	var min, max float64
+	var minX, minY, maxX, maxY int
+	for nx := x0; nx <= x2; nx++ {
+		for ny := y0; ny <= y2; ny++ {
+			if nx == x && ny == y {
+				continue
+			}
+			delta := colorDelta(img, img, x, y, nx, ny, true)
+			switch {
+			case delta == 0:
+				zeroes++
+				if zeroes > 2 {
+					return false // the pixel is in a flat area
+				}
+			case delta < min:
+				min, minX, minY = delta, nx, ny
+			case delta > max:
+				max, maxX, maxY = delta, nx, ny
+			}
+		}
+	}
+	if min == 0 || max == 0 {
+		return false // no darker or no brighter neighbour
+	}
+
+	return (manySiblings(img, minX, minY) && manySiblings(other, minX, minY)) ||
+		(manySiblings(img, maxX, maxY) && manySiblings(other, maxX, maxY))
+}
+
+// manySiblings returns true if the pixel at x, y has more than 2 identical
+// neighbours.
+func manySiblings(img *image.NRGBA, x, y int) bool {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	x0, y0 := imax(x-1, 0), imax(y-1, 0)
+	x2, y2 := imin(x+1, w-1), imin(y+1, h-1)
+
+	zeroes := 0
+	if x == x0 || x == x2 || y == y0 || y == y2 {
+		zeroes = 1
+	}
+
+	c := img.NRGBAAt(x, y)
+	for nx := x0; nx <= x2; nx++ {
+		for ny := y0; ny <= y2; ny++ {
+			if nx == x && ny == y {
+				continue
+			}
+			if img.NRGBAAt(nx, ny) == c {
+				zeroes++
+			}
+			if zeroes > 2 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// colorDelta returns the squared YIQ distance between two pixels, negative
+// when the first one is brighter, or only the brightness difference if
+// yOnly is true.
+func colorDelta(img1, img2 *image.NRGBA, x1, y1, x2, y2 int, yOnly bool) float64 {
+	c1, c2 := img1.NRGBAAt(x1, y1), img2.NRGBAAt(x2, y2)
+	if c1 == c2 {
+		return 0
+	}
+
+	r1, g1, b1 := blendWhite(c1)
+	r2, g2, b2 := blendWhite(c2)
+
+	yy1, yy2 := rgb2y(r1, g1, b1), rgb2y(r2, g2, b2)
+	dy := yy1 - yy2
+	if yOnly {
+		return dy
+	}
+
+	di := rgb2i(r1, g1, b1) - rgb2i(r2, g2, b2)
+	dq := rgb2q(r1, g1, b1) - rgb2q(r2, g2, b2)
+	delta := 0.5053*dy*dy + 0.299*di*di + 0.1957*dq*dq
+	if yy1 > yy2 {
+		return -delta
+	}
+	return delta
+}
+
+// blendWhite returns the color components of c blended over white.
+func blendWhite(c color.NRGBA) (r, g, b float64) {
+	a := float64(c.A) / 255
+	return blend(float64(c.R), a), blend(float64(c.G), a), blend(float64(c.B), a)
+}
+
+func blend(c, a float64) float64 {
+	return 255 + (c-255)*a
+}
+
+func rgb2y(r, g, b float64) float64 { return r*0.29889531 + g*0.58662247 + b*0.11448223 }
+func rgb2i(r, g, b float64) float64 { return r*0.59597799 - g*0.27417610 - b*0.32180189 }
+func rgb2q(r, g, b float64) float64 { return r*0.21147017 - g*0.52261711 + b*0.31114694 }
+
+// drawGray draws the pixel of img at x, y faded to gray in dst.
+func drawGray(dst, img *image.NRGBA, x, y int) {
+	r, g, b := blendWhite(img.NRGBAAt(x, y))
+	v := uint8(blend(rgb2y(r, g, b), 0.1))
+	dst.SetNRGBA(x, y, color.NRGBA{R: v, G: v, B: v, A: 255})
+}
+
+// blendOver returns c drawn over the opaque color dst.
+func blendOver(dst, c color.NRGBA) color.NRGBA {
+	a := float64(c.A) / 255
+	mix := func(d, s uint8) uint8 { return uint8(float64(d)*(1-a) + float64(s)*a) }
+	return color.NRGBA{R: mix(dst.R, c.R), G: mix(dst.G, c.G), B: mix(dst.B, c.B), A: 255}
+}
+
+func imin(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func imax(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,131 @@
+package visual
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	t.Run("IdenticalTest", IdenticalTest)
+	t.Run("DiffPixelsTest", DiffPixelsTest)
+	t.Run("ToleranceTest", ToleranceTest)
+	t.Run("IgnoreTest", IgnoreTest)
+	t.Run("AntiAliasingTest", AntiAliasingTest)
+	t.Run("SizeMismatchTest", SizeMismatchTest)
+}
+
+func uniform(w, h int, c color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+// edge returns a black and white image with a column of gray in between.
+func edge(gray uint8) *image.NRGBA {
+	img := uniform(10, 10, color.White)
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 4; x++ {
+			img.Set(x, y, color.Black)
+		}
+		img.Set(4, y, color.Gray{Y: gray})
+	}
+	return img
+}
+
+func IdenticalTest(t *testing.T) {
+	res, err := Compare(edge(128), edge(128), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Ok() || res.DiffPixels != 0 || res.AntiAliasedPixels != 0 {
+		t.Fatalf("unexpected result: %v", res)
+	}
+}
+
+func DiffPixelsTest(t *testing.T) {
+	got := uniform(10, 10, color.White)
+	got.Set(2, 3, color.Black)
+	got.Set(7, 7, color.Black)
+
+	res, err := Compare(got, uniform(10, 10, color.White), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Ok() || res.DiffPixels != 2 {
+		t.Fatalf("unexpected result: %v", res)
+	}
+	if c := res.Diff.NRGBAAt(2, 3); c != DiffColor {
+		t.Fatalf("expected the diff color, got %v", c)
+	}
+	if c := res.Diff.NRGBAAt(0, 0); c.R != c.G || c.R != c.B {
+		t.Fatalf("expected a gray pixel, got %v", c)
+	}
+
+	res, _ = Compare(got, uniform(10, 10, color.White), Options{MaxDiffPixels: 2})
+	if !res.Ok() {
+		t.Fatalf("expected 2 pixels to be allowed: %v", res)
+	}
+}
+
+func ToleranceTest(t *testing.T) {
+	got := uniform(4, 4, color.Gray{Y: 250})
+	want := uniform(4, 4, color.White)
+
+	if res, _ := Compare(got, want, Options{}); res.DiffPixels != 16 {
+		t.Fatalf("expected all pixels to differ: %v", res)
+	}
+	if res, _ := Compare(got, want, Options{Tolerance: 0.1}); res.DiffPixels != 0 {
+		t.Fatalf("expected all pixels to be tolerated: %v", res)
+	}
+}
+
+func IgnoreTest(t *testing.T) {
+	got := uniform(10, 10, color.White)
+	got.Set(2, 3, color.Black)
+	got.Set(7, 7, color.Black)
+
+	res, err := Compare(got, uniform(10, 10, color.White), Options{
+		Ignore: []image.Rectangle{image.Rect(0, 0, 5, 5)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.DiffPixels != 1 {
+		t.Fatalf("expected 1 different pixel: %v", res)
+	}
+	if c := res.Diff.NRGBAAt(2, 3); c.B <= c.R {
+		t.Fatalf("expected the ignored color, got %v", c)
+	}
+}
+
+func AntiAliasingTest(t *testing.T) {
+	got, want := edge(100), edge(160)
+
+	res, err := Compare(got, want, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Ok() || res.AntiAliasedPixels != 10 {
+		t.Fatalf("expected the edge to be anti-aliased: %v", res)
+	}
+	if c := res.Diff.NRGBAAt(4, 4); c != AntiAliasedColor {
+		t.Fatalf("expected the anti-aliased color, got %v", c)
+	}
+
+	res, _ = Compare(got, want, Options{StrictAntiAliasing: true})
+	if res.DiffPixels != 10 {
+		t.Fatalf("expected strict differences: %v", res)
+	}
+}
+
+func SizeMismatchTest(t *testing.T) {
+	_, err := Compare(uniform(2, 2, color.White), uniform(2, 3, color.White), Options{})
+	if _, ok := err.(*SizeError); !ok {
+		t.Fatalf("expected a SizeError, got %v", err)
+	}
+}
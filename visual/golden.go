@@ -0,0 +1,174 @@
+package visual
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"image"
+	"image/png"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mcluseau/marionette"
+)
+
+// GoldenDir is the directory of the golden images, relative to the package
+// being tested.
+var GoldenDir = filepath.Join("testdata", "golden")
+
+// Update rewrites the golden images instead of comparing with them, when
+// tests run with -visual.update.
+var Update = flag.Bool("visual.update", false, "rewrite the golden images of visual.Match")
+
+func goldenPath(name string) string {
+	return filepath.Join(GoldenDir, name+".png")
+}
+
+func diffPath(name string) string {
+	return filepath.Join(GoldenDir, name+".diff.png")
+}
+
+// Match compares img with the golden image name in GoldenDir, and fails the
+// test if they differ, writing the diff image next to the golden one.
+//
+// A missing golden image fails the test too, unless Update is set: then
+// img is written as the golden image.
+func Match(t testing.TB, name string, img image.Image, opts Options) {
+	t.Helper()
+
+	path := goldenPath(name)
+	if *Update {
+		if err := writePNG(path, img); err != nil {
+			t.Fatal(err)
+		}
+		os.Remove(diffPath(name))
+		return
+	}
+
+	golden, err := readPNG(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("no golden image %s, run with -visual.update to create it", path)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Compare(img, golden, opts)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if res.Ok() {
+		os.Remove(diffPath(name))
+		return
+	}
+	if err = writePNG(diffPath(name), res.Diff); err != nil {
+		t.Error(err)
+	}
+	t.Fatalf("%s: %v, see %s", name, res, diffPath(name))
+}
+
+// MatchPage takes a full page screenshot and matches it with the golden image
+// name. See Match.
+func MatchPage(t testing.TB, name string, c *marionette.Client, opts Options) {
+	t.Helper()
+
+	img, err := c.ScreenshotImage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opts.IgnoreElements) != 0 {
+		r, err := c.ExecuteScript("return window.devicePixelRatio;", []any{}, time.Second, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out struct {
+			Value float64 `json:"value"`
+		}
+		if err = json.Unmarshal([]byte(r.Value), &out); err != nil {
+			t.Fatal(err)
+		}
+		if err = ignoreElements(&opts, marionette.Point{}, out.Value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	Match(t, name, img, opts)
+}
+
+// MatchElement takes a screenshot of e and matches it with the golden image
+// name. See Match.
+func MatchElement(t testing.TB, name string, e *marionette.WebElement, opts Options) {
+	t.Helper()
+
+	img, err := e.ScreenshotImage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opts.IgnoreElements) != 0 {
+		rect, err := e.Rect()
+		if err != nil {
+			t.Fatal(err)
+		}
+		scale := 1.0
+		if rect.Width > 0 {
+			scale = float64(img.Bounds().Dx()) / rect.Width
+		}
+		if err = ignoreElements(&opts, rect.Point, scale); err != nil {
+			t.Fatal(err)
+		}
+	}
+	Match(t, name, img, opts)
+}
+
+// ignoreElements adds the regions of opts.IgnoreElements to opts.Ignore, in
+// the coordinates of an image whose top-left corner is at origin in the page
+// and scaled by scale.
+func ignoreElements(opts *Options, origin marionette.Point, scale float64) error {
+	ignore := append([]image.Rectangle{}, opts.Ignore...)
+	for _, e := range opts.IgnoreElements {
+		rect, err := e.Rect()
+		if err != nil {
+			return err
+		}
+		ignore = append(ignore, elementRegion(rect, origin, scale))
+	}
+	opts.Ignore = ignore
+	return nil
+}
+
+// elementRegion returns the pixels covered by rect in an image whose top-left
+// corner is at origin and scaled by scale.
+func elementRegion(rect *marionette.ElementRect, origin marionette.Point, scale float64) image.Rectangle {
+	x := float64(rect.X - origin.X)
+	y := float64(rect.Y - origin.Y)
+	return image.Rect(
+		int(math.Floor(x*scale)), int(math.Floor(y*scale)),
+		int(math.Ceil((x+rect.Width)*scale)), int(math.Ceil((y+rect.Height)*scale)),
+	)
+}
+
+func readPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+func writePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
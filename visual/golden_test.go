@@ -0,0 +1,104 @@
+package visual
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/mcluseau/marionette"
+)
+
+func TestGolden(t *testing.T) {
+	t.Run("MatchTest", MatchTest)
+	t.Run("ElementRegionTest", ElementRegionTest)
+}
+
+// fakeT records the failure of a test.
+type fakeT struct {
+	testing.TB
+	failure string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Error(args ...any) { t.failure = fmt.Sprint(args...) }
+
+func (t *fakeT) Fatal(args ...any) {
+	t.failure = fmt.Sprint(args...)
+	runtime.Goexit()
+}
+
+func (t *fakeT) Fatalf(format string, args ...any) {
+	t.failure = fmt.Sprintf(format, args...)
+	runtime.Goexit()
+}
+
+// match runs Match with a fakeT and returns the failure, if any.
+func match(name string, img image.Image, opts Options) string {
+	ft := &fakeT{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Match(ft, name, img, opts)
+	}()
+	<-done
+	return ft.failure
+}
+
+func MatchTest(t *testing.T) {
+	defer func(dir string) { GoldenDir = dir }(GoldenDir)
+	GoldenDir = t.TempDir()
+
+	img := uniform(10, 10, color.White)
+
+	if f := match("page", img, Options{}); f == "" {
+		t.Fatal("expected a failure without golden image")
+	}
+
+	*Update = true
+	f := match("page", img, Options{})
+	*Update = false
+	if f != "" {
+		t.Fatal(f)
+	}
+	if _, err := os.Stat(goldenPath("page")); err != nil {
+		t.Fatal(err)
+	}
+
+	if f = match("page", img, Options{}); f != "" {
+		t.Fatal(f)
+	}
+
+	changed := uniform(10, 10, color.White)
+	changed.Set(5, 5, color.Black)
+	if f = match("page", changed, Options{}); f == "" {
+		t.Fatal("expected a failure on a different image")
+	}
+	if _, err := os.Stat(diffPath("page")); err != nil {
+		t.Fatal(err)
+	}
+
+	if f = match("page", changed, Options{MaxDiffPixels: 1}); f != "" {
+		t.Fatal(f)
+	}
+	if _, err := os.Stat(diffPath("page")); !os.IsNotExist(err) {
+		t.Fatal("expected the diff image to be removed")
+	}
+}
+
+func ElementRegionTest(t *testing.T) {
+	rect := &marionette.ElementRect{
+		Point: marionette.Point{X: 15.5, Y: 20},
+		Size:  marionette.Size{Width: 10, Height: 5},
+	}
+
+	if r := elementRegion(rect, marionette.Point{}, 1); r != image.Rect(15, 20, 26, 25) {
+		t.Fatalf("unexpected region %v", r)
+	}
+	if r := elementRegion(rect, marionette.Point{X: 10, Y: 10}, 2); r != image.Rect(11, 20, 31, 30) {
+		t.Fatalf("unexpected region %v", r)
+	}
+}
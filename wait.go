@@ -1,13 +1,36 @@
 package marionette
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"runtime"
+	"strings"
 	"time"
 )
 
+// DefaultPollInterval is the delay between two evaluations of a condition
+// when none is set with Waiter.Poll.
+const DefaultPollInterval = time.Second
+
+// DefaultIgnoredErrors are the DriverError types that don't stop a Waiter,
+// as they usually mean the page is not ready yet.
+var DefaultIgnoredErrors = []string{ERROR_NO_SUCH_ELEMENT, ERROR_STALE_ELEMENT_REFERENCE}
+
 type Waiter struct {
-	f Finder
-	d time.Duration // Milliseconds
+	f   Finder
+	ctx context.Context
+	d   time.Duration
+
+	poll       time.Duration
+	backoff    float64
+	maxPoll    time.Duration
+	jitter     float64
+	ignored    []string
+	ignoredErr []error
+	desc       string
 }
 
 type Finder interface {
@@ -16,34 +39,247 @@ type Finder interface {
 }
 
 func Wait(f Finder) *Waiter {
-	return &Waiter{f: f, d: time.Duration(1)}
+	return &Waiter{
+		f:       f,
+		ctx:     context.Background(),
+		poll:    DefaultPollInterval,
+		ignored: DefaultIgnoredErrors,
+	}
 }
 
+// For sets the maximum duration of the wait. A zero or negative duration
+// evaluates the condition only once, unless a context is set with Context.
 func (w *Waiter) For(d time.Duration) *Waiter {
-	if d < 0 || d > time.Minute*10 {
-		w.d = time.Duration(time.Second)
-		return w
-	}
-
 	w.d = d
 	return w
 }
 
+// Context bounds the wait with ctx, in addition to For's duration.
+func (w *Waiter) Context(ctx context.Context) *Waiter {
+	w.ctx = ctx
+	return w
+}
+
+// Poll sets the delay between two evaluations of the condition.
+func (w *Waiter) Poll(interval time.Duration) *Waiter {
+	w.poll = interval
+	return w
+}
+
+// Backoff multiplies the poll interval by factor after each evaluation of the
+// condition, without exceeding max (if positive).
+func (w *Waiter) Backoff(factor float64, max time.Duration) *Waiter {
+	w.backoff = factor
+	w.maxPoll = max
+	return w
+}
+
+// Jitter randomizes each delay by up to the given fraction of it (0.1 for
+// +/- 10%).
+func (w *Waiter) Jitter(fraction float64) *Waiter {
+	w.jitter = fraction
+	return w
+}
+
+// Ignoring sets the DriverError types that don't stop the wait, replacing
+// DefaultIgnoredErrors.
+func (w *Waiter) Ignoring(errorTypes ...string) *Waiter {
+	w.ignored = errorTypes
+	return w
+}
+
+// IgnoringErrors adds errors that don't stop the wait, as matched by
+// errors.Is.
+func (w *Waiter) IgnoringErrors(errs ...error) *Waiter {
+	w.ignoredErr = append(w.ignoredErr, errs...)
+	return w
+}
+
+// Describe sets the condition description used in timeout errors. It
+// defaults to the condition function name.
+func (w *Waiter) Describe(desc string) *Waiter {
+	w.desc = desc
+	return w
+}
+
+// Until evaluates the condition until it is true, it returns an error that
+// is not ignored, or the wait times out with a TimeoutError.
+//
+// When the condition is true, errors it returned along are ignored.
 func (w *Waiter) Until(f func(c Finder) (bool, *WebElement, error)) (bool, *WebElement, error) {
-	firstRun := true
-	delta := time.Now()
-	for time.Since(delta) < w.d || firstRun {
-		firstRun = false
+	var value *WebElement
+	err := w.run(funcName(f), func(ctx context.Context) (bool, error) {
+		ok, v, err := f(w.f)
+		if ok {
+			value = v
+		}
+		return ok, err
+	})
+	if err != nil {
+		return false, nil, err
+	}
+	return true, value, nil
+}
+
+func (w *Waiter) ignores(err error) bool {
+	if len(w.ignored) != 0 && IsDriverError(err, w.ignored...) {
+		return true
+	}
+	for _, ierr := range w.ignoredErr {
+		if errors.Is(err, ierr) {
+			return true
+		}
+	}
+	return false
+}
 
-		ok, value, err := f(w.f)
-		if _, is := err.(*DriverError); is {
-			return false, nil, err
-		} else if ok {
-			return true, value, err
+// next returns the delay before the next evaluation and the one after it.
+func (w *Waiter) next(interval time.Duration) (sleep, nextInterval time.Duration) {
+	sleep = interval
+	if w.jitter > 0 {
+		sleep += time.Duration((rand.Float64()*2 - 1) * w.jitter * float64(interval))
+	}
+
+	nextInterval = interval
+	if w.backoff > 1 {
+		nextInterval = time.Duration(float64(interval) * w.backoff)
+		if w.maxPoll > 0 && nextInterval > w.maxPoll {
+			nextInterval = w.maxPoll
+		}
+	}
+	return
+}
+
+// run is the polling loop shared by all the waits.
+func (w *Waiter) run(name string, check func(ctx context.Context) (bool, error)) error {
+	ctx := w.ctx
+	if w.d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.d)
+		defer cancel()
+	}
+
+	if w.desc != "" {
+		name = w.desc
+	}
+
+	start := time.Now()
+	interval := w.poll
+	attempts := 0
+	var lastErr error
+
+	for {
+		attempts++
+		ok, err := check(ctx)
+		if ok {
+			return nil
+		}
+		if err != nil {
+			if !w.ignores(err) {
+				return err
+			}
+			lastErr = err
+		}
+
+		if w.d <= 0 && ctx.Done() == nil {
+			break // single evaluation
 		}
 
-		time.Sleep(time.Second)
+		var sleep time.Duration
+		sleep, interval = w.next(interval)
+
+		timer := time.NewTimer(sleep)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return &TimeoutError{
+				Condition: name,
+				Elapsed:   time.Since(start),
+				Attempts:  attempts,
+				LastError: lastErr,
+				Err:       ctx.Err(),
+			}
+		case <-timer.C:
+		}
 	}
 
-	return false, nil, errors.New("condition never occurred")
+	return &TimeoutError{
+		Condition: name,
+		Elapsed:   time.Since(start),
+		Attempts:  attempts,
+		LastError: lastErr,
+	}
+}
+
+// TimeoutError is returned when a condition never occurred during a wait.
+type TimeoutError struct {
+	// Condition describes what was waited for.
+	Condition string
+	Elapsed   time.Duration
+	Attempts  int
+	// LastError is the last ignored error returned by the condition.
+	LastError error
+	// Err is the context error that ended the wait, if any.
+	Err error
+}
+
+func (e *TimeoutError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "condition never occurred: %s (%d attempts in %v)",
+		e.Condition, e.Attempts, e.Elapsed.Round(time.Millisecond))
+	if e.LastError != nil {
+		fmt.Fprintf(&b, ", last error: %v", e.LastError)
+	}
+	return b.String()
+}
+
+func (e *TimeoutError) Unwrap() []error {
+	var errs []error
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	if e.LastError != nil {
+		errs = append(errs, e.LastError)
+	}
+	return errs
+}
+
+// funcName returns a short name of f, like "ElementIsPresent".
+func funcName(f any) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(f).Pointer())
+	if fn == nil {
+		return "condition"
+	}
+	name := fn.Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	name = strings.TrimPrefix(name, "marionette.")
+	for {
+		i := strings.LastIndex(name, ".func")
+		if i <= 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return name
+}
+
+// Condition is a condition evaluated by WaitFor. It returns a value and
+// whether the condition is met.
+type Condition[T any] func(ctx context.Context) (T, bool, error)
+
+// WaitFor evaluates cond with the settings of w until it is met, and returns
+// its value. It stops on errors that w doesn't ignore, and times out with a
+// TimeoutError.
+//
+//	title, err := WaitFor(Wait(client).For(10*time.Second), TitleContains(client, "Dashboard"))
+func WaitFor[T any](w *Waiter, cond Condition[T]) (T, error) {
+	var value T
+	err := w.run(funcName(cond), func(ctx context.Context) (bool, error) {
+		v, ok, err := cond(ctx)
+		if ok {
+			value = v
+		}
+		return ok, err
+	})
+	return value, err
 }
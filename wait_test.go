@@ -1,14 +1,20 @@
 package marionette
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 )
 
 func TestWait(t *testing.T) {
 	t.Run("UntilConditionNeverOccuredTest", UntilConditionNeverOccuredTest)
-	// FIXME: t.Run("UntilErrorTest", UntilErrorTest)
+	t.Run("UntilErrorTest", UntilErrorTest)
+	t.Run("UntilIgnoredErrorTest", UntilIgnoredErrorTest)
+	t.Run("UntilContextTest", UntilContextTest)
+	t.Run("BackoffTest", BackoffTest)
+	t.Run("JitterTest", JitterTest)
 }
 
 func UntilErrorTest(t *testing.T) {
@@ -25,15 +31,108 @@ func UntilErrorTest(t *testing.T) {
 }
 
 func UntilConditionNeverOccuredTest(t *testing.T) {
-	timeout := time.Duration(11) * time.Minute
+	timeout := time.Duration(500) * time.Millisecond
 	condition := func(c Finder) (bool, *WebElement, error) {
 		return false, nil, nil
 	}
-	_, _, err := Wait(client).For(timeout).Until(condition)
+	_, _, err := Wait(client).For(timeout).Poll(100 * time.Millisecond).Until(condition)
 
 	if err == nil {
 		t.Fatal("Element Was Found in ElementIsNotPresent test.")
 	}
+
+	var te *TimeoutError
+	if !errors.As(err, &te) {
+		t.Fatalf("expected a TimeoutError, got %#v", err)
+	}
+	if te.Attempts < 2 || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected timeout error %#v", te)
+	}
+	if !strings.Contains(err.Error(), "UntilConditionNeverOccuredTest") {
+		t.Fatalf("condition not named in error: %v", err)
+	}
+}
+
+func UntilIgnoredErrorTest(t *testing.T) {
+	notFound := &DriverError{ErrorType: ERROR_NO_SUCH_ELEMENT, Message: "Unable to locate element: #late"}
+	attempts := 0
+	condition := func(c Finder) (bool, *WebElement, error) {
+		attempts++
+		if attempts < 3 {
+			return false, nil, notFound
+		}
+		return true, new(WebElement), nil
+	}
+
+	ok, v, err := Wait(client).For(time.Second).Poll(time.Millisecond).Until(condition)
+	if !ok || v == nil || err != nil {
+		t.Fatalf("unexpected result: %v %v %#v", ok, v, err)
+	}
+
+	// not ignored anymore
+	attempts = 0
+	_, _, err = Wait(client).For(time.Second).Poll(time.Millisecond).Ignoring().Until(condition)
+	if err != notFound {
+		t.Fatalf("expected %#v, got %#v", notFound, err)
+	}
+
+	// last error is reported
+	condition = func(c Finder) (bool, *WebElement, error) {
+		return false, nil, notFound
+	}
+	_, _, err = Wait(client).For(50 * time.Millisecond).Poll(time.Millisecond).Describe("#late is present").Until(condition)
+	if !strings.Contains(err.Error(), "#late is present") || !strings.Contains(err.Error(), notFound.Message) {
+		t.Fatalf("unexpected error message: %v", err)
+	}
+	if !IsDriverError(err, ERROR_NO_SUCH_ELEMENT) {
+		t.Fatalf("last error should be unwrapped: %#v", err)
+	}
+}
+
+func UntilContextTest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	condition := func(c Finder) (bool, *WebElement, error) {
+		cancel()
+		return false, nil, nil
+	}
+
+	_, _, err := Wait(client).For(time.Minute).Context(ctx).Until(condition)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %#v", err)
+	}
+}
+
+func BackoffTest(t *testing.T) {
+	w := Wait(client).Poll(100*time.Millisecond).Backoff(2, 300*time.Millisecond)
+
+	interval := w.poll
+	var sleeps []time.Duration
+	for i := 0; i < 4; i++ {
+		var sleep time.Duration
+		sleep, interval = w.next(interval)
+		sleeps = append(sleeps, sleep)
+	}
+
+	expected := []time.Duration{100, 200, 300, 300}
+	for i := range expected {
+		if sleeps[i] != expected[i]*time.Millisecond {
+			t.Fatalf("expected sleeps %v ms, got %v", expected, sleeps)
+		}
+	}
+}
+
+func JitterTest(t *testing.T) {
+	w := Wait(client).Poll(100 * time.Millisecond).Jitter(0.5)
+
+	for i := 0; i < 100; i++ {
+		sleep, next := w.next(w.poll)
+		if sleep < 50*time.Millisecond || sleep > 150*time.Millisecond {
+			t.Fatalf("sleep out of jitter range: %v", sleep)
+		}
+		if next != w.poll {
+			t.Fatalf("interval changed without backoff: %v", next)
+		}
+	}
 }
 
 func WaitForUntilIntegrationTest(t *testing.T) {
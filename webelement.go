@@ -29,10 +29,13 @@ type ElementRect struct {
 }
 
 type WebElement struct {
-	id string //`json:"element-6066-11e4-a52e-4f735466cecf"`
-	c  *Client
+	id  string //`json:"element-6066-11e4-a52e-4f735466cecf"`
+	c   *Client
+	loc *elementLocator
 }
 
+// Id returns the web element reference. It is empty for elements populated
+// by PopulateLazy until they are used.
 func (e *WebElement) Id() string {
 	return e.id
 }
@@ -41,32 +44,47 @@ func (e *WebElement) GetActiveElement() (*WebElement, error) {
 	return e.c.GetActiveElement()
 }
 
-func (e *WebElement) FindElement(by By, value string) (*WebElement, error) {
-	return e.c.findElement(by, value, &e.id)
+func (e *WebElement) FindElement(by By, value string) (found *WebElement, err error) {
+	err = e.heal(func() (err error) {
+		found, err = e.c.findElement(by, value, &e.id)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	found.loc = &elementLocator{parent: e, by: by, value: value, index: -1}
+	return found, nil
 }
 
-func (e *WebElement) FindElements(by By, value string) ([]*WebElement, error) {
-	return e.c.findElements(by, value, &e.id)
+func (e *WebElement) FindElements(by By, value string) (found []*WebElement, err error) {
+	err = e.heal(func() (err error) {
+		found, err = e.c.findElements(by, value, &e.id)
+		return
+	})
+	for i, f := range found {
+		f.loc = &elementLocator{parent: e, by: by, value: value, index: i}
+	}
+	return found, err
 }
 
 func (e *WebElement) Enabled() bool {
-	return isElementEnabled(e.c, e.id)
+	return isElementEnabled(e)
 }
 
 func (e *WebElement) Selected() bool {
-	return isElementSelected(e.c, e.id)
+	return isElementSelected(e)
 }
 
 func (e *WebElement) Displayed() bool {
-	return isElementDisplayed(e.c, e.id)
+	return isElementDisplayed(e)
 }
 
 func (e *WebElement) TagName() string {
-	return getElementTagName(e.c, e.id)
+	return getElementTagName(e)
 }
 
 func (e *WebElement) Text() string {
-	return getElementText(e.c, e.id)
+	return getElementText(e)
 }
 
 func Attribute[T any](e *WebElement, name string) (T, error) {
@@ -78,8 +96,8 @@ func Attribute[T any](e *WebElement, name string) (T, error) {
 }
 
 func (e *WebElement) getAttribute(name string, dest any) error {
-	r, err := e.c.tr.Send("WebDriver:GetElementAttribute", map[string]any{
-		"id": e.id, "name": name,
+	r, err := e.send("WebDriver:GetElementAttribute", map[string]any{
+		"name": name,
 	})
 	if err != nil {
 		return err
@@ -100,8 +118,8 @@ func Property[T any](e *WebElement, name string) (T, error) {
 }
 
 func (e *WebElement) getProperty(name string, dest any) error {
-	r, err := e.c.tr.Send("WebDriver:GetElementProperty", map[string]any{
-		"id": e.id, "name": name,
+	r, err := e.send("WebDriver:GetElementProperty", map[string]any{
+		"name": name,
 	})
 	if err != nil {
 		return err
@@ -130,8 +148,8 @@ func (e *WebElement) PropertyString(name string) (string, error) {
 }
 
 func (e *WebElement) cssValue(property string, dest any) error {
-	r, err := e.c.tr.Send("WebDriver:GetElementCSSValue", map[string]any{
-		"id": e.id, "propertyName": property,
+	r, err := e.send("WebDriver:GetElementCSSValue", map[string]any{
+		"propertyName": property,
 	})
 	if err != nil {
 		return err
@@ -148,9 +166,7 @@ func (e *WebElement) CssValue(property string) (any, error) {
 }
 
 func (e *WebElement) Rect() (*ElementRect, error) {
-	r, err := e.c.tr.Send("WebDriver:GetElementRect", map[string]any{
-		"id": e.id,
-	})
+	r, err := e.send("WebDriver:GetElementRect", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -162,14 +178,12 @@ func (e *WebElement) Rect() (*ElementRect, error) {
 	return d, nil
 }
 
-func (e *WebElement) Click() {
-	r, err := e.c.tr.Send("WebDriver:ElementClick", map[string]any{"id": e.id})
-	if err != nil {
-		return
+func (e *WebElement) Click() error {
+	if err := e.autoWait(clickChecks); err != nil {
+		return err
 	}
-
-	var d = map[string]any{}
-	json.Unmarshal([]byte(r.Value), &d)
+	_, err := e.send("WebDriver:ElementClick", nil)
+	return err
 }
 
 func (e *WebElement) SendKeys(keys string) error {
@@ -179,7 +193,10 @@ func (e *WebElement) SendKeys(keys string) error {
 	//}
 	//
 	//r, err := c.transport.Send("sendKeysToElement", map[string]any{"id": id, "value": slice})
-	r, err := e.c.tr.Send("WebDriver:ElementSendKeys", map[string]any{"id": e.id, "text": keys})
+	if err := e.autoWait(inputChecks); err != nil {
+		return err
+	}
+	r, err := e.send("WebDriver:ElementSendKeys", map[string]any{"text": keys})
 	if err != nil {
 		return err
 	}
@@ -190,14 +207,12 @@ func (e *WebElement) SendKeys(keys string) error {
 	return nil
 }
 
-func (e *WebElement) Clear() {
-	r, err := e.c.tr.Send("WebDriver:ElementClear", map[string]any{"id": e.id})
-	if err != nil {
-		return
+func (e *WebElement) Clear() error {
+	if err := e.autoWait(inputChecks); err != nil {
+		return err
 	}
-
-	var d = map[string]any{}
-	json.Unmarshal([]byte(r.Value), &d)
+	_, err := e.send("WebDriver:ElementClear", nil)
+	return err
 }
 
 func (e *WebElement) Location() (*Point, error) {
@@ -216,14 +231,20 @@ func (e *WebElement) Size() (*Size, error) {
 	return &r.Size, nil
 }
 
-func (e *WebElement) Screenshot() ([]byte, error) {
-	id := e.Id()
-	return e.c.takeScreenshot(&id)
+func (e *WebElement) Screenshot() (data []byte, err error) {
+	err = e.heal(func() (err error) {
+		data, err = e.c.takeScreenshot(&e.id)
+		return
+	})
+	return
 }
 
-func (e *WebElement) ScreenshotImage() (image.Image, error) {
-	id := e.Id()
-	return e.c.takeScreenshotImage(&id)
+func (e *WebElement) ScreenshotImage() (img image.Image, err error) {
+	err = e.heal(func() (err error) {
+		img, err = e.c.takeScreenshotImage(&e.id)
+		return
+	})
+	return
 }
 
 func (e *WebElement) UnmarshalJSON(data []byte) error {
@@ -0,0 +1,188 @@
+package marionette
+
+import (
+	"context"
+	"time"
+)
+
+// newWindowPollInterval is the delay between two window handles lookups when
+// waiting for new windows.
+const newWindowPollInterval = 100 * time.Millisecond
+
+// Window is a top-level browsing context (a tab or a window).
+//
+// Its methods switch to the window before executing, so code juggling with
+// several windows doesn't have to track the current handle.
+type Window struct {
+	Handle string `json:"handle"`
+	// Type is "tab" or "window", empty when unknown.
+	Type    string `json:"type"`
+	Private bool   `json:"-"`
+
+	c *Client
+}
+
+// Window returns the window of the given handle.
+func (c *Client) Window(handle string) *Window {
+	return &Window{Handle: handle, c: c}
+}
+
+// CurrentWindow returns the current window.
+func (c *Client) CurrentWindow() (*Window, error) {
+	handle, err := c.GetWindowHandle()
+	if err != nil {
+		return nil, err
+	}
+	c.window = handle
+	return c.Window(handle), nil
+}
+
+// Windows returns all the windows currently opened.
+func (c *Client) Windows() ([]*Window, error) {
+	handles, err := c.GetWindowHandles()
+	if err != nil {
+		return nil, err
+	}
+	windows := make([]*Window, len(handles))
+	for i, handle := range handles {
+		windows[i] = c.Window(handle)
+	}
+	return windows, nil
+}
+
+// Switch makes w the current window, with its top-level document as the
+// current frame.
+func (w *Window) Switch() error {
+	if w.c.window == w.Handle {
+		if len(w.c.frames) == 0 {
+			return nil
+		}
+		return w.c.SwitchToTopFrame()
+	}
+	return w.c.SwitchToWindow(w.Handle)
+}
+
+// Navigate opens url in the window.
+func (w *Window) Navigate(url string) (*Response, error) {
+	if err := w.Switch(); err != nil {
+		return nil, err
+	}
+	return w.c.Navigate(url)
+}
+
+// Title returns the title of the window's document.
+func (w *Window) Title() (string, error) {
+	if err := w.Switch(); err != nil {
+		return "", err
+	}
+	return w.c.Title()
+}
+
+// URL returns the URL of the window's document.
+func (w *Window) URL() (string, error) {
+	if err := w.Switch(); err != nil {
+		return "", err
+	}
+	return w.c.URL()
+}
+
+// FindElement finds an element in the window.
+func (w *Window) FindElement(by By, value string) (*WebElement, error) {
+	if err := w.Switch(); err != nil {
+		return nil, err
+	}
+	return w.c.FindElement(by, value)
+}
+
+// FindElements finds elements in the window.
+func (w *Window) FindElements(by By, value string) ([]*WebElement, error) {
+	if err := w.Switch(); err != nil {
+		return nil, err
+	}
+	return w.c.FindElements(by, value)
+}
+
+// Rect gets the window position and size.
+func (w *Window) Rect() (*WindowRect, error) {
+	if err := w.Switch(); err != nil {
+		return nil, err
+	}
+	return w.c.GetWindowRect()
+}
+
+// SetRect sets the window position and size.
+func (w *Window) SetRect(rect WindowRect) error {
+	if err := w.Switch(); err != nil {
+		return err
+	}
+	return w.c.SetWindowRect(rect)
+}
+
+// Close closes the window.
+func (w *Window) Close() error {
+	if err := w.Switch(); err != nil {
+		return err
+	}
+	_, err := w.c.CloseWindow()
+	return err
+}
+
+// WaitForNewWindow runs action, typically a click on a target=_blank link,
+// and waits for the window it opens. If switchTo is true, the new window is
+// made current.
+func (c *Client) WaitForNewWindow(ctx context.Context, action func() error, switchTo bool) (*Window, error) {
+	windows, err := c.WaitForNewWindows(ctx, 1, action)
+	if err != nil {
+		return nil, err
+	}
+	w := windows[0]
+	if switchTo {
+		if err = w.Switch(); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// WaitForNewWindows runs action and waits until at least count windows that
+// were not opened before appear, returning only those new windows.
+func (c *Client) WaitForNewWindows(ctx context.Context, count int, action func() error) ([]*Window, error) {
+	handles, err := c.GetWindowHandles()
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(handles))
+	for _, handle := range handles {
+		known[handle] = true
+	}
+
+	if err = action(); err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(newWindowPollInterval)
+	defer ticker.Stop()
+
+	for {
+		handles, err = c.GetWindowHandles()
+		if err != nil {
+			return nil, err
+		}
+
+		var windows []*Window
+		for _, handle := range handles {
+			if !known[handle] {
+				windows = append(windows, c.Window(handle))
+			}
+		}
+		if len(windows) >= count {
+			return windows, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package marionette
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// required test in sequential main client test: client_test.go
+func WindowTest(t *testing.T) {
+	first, err := client.CurrentWindow()
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	second, err := client.NewWindow(false, "tab", false)
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if second.Handle == "" || second.Type != "tab" {
+		t.Fatalf("unexpected new window: %#v", second)
+	}
+
+	_, err = first.Navigate(localURL("table.html"))
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	_, err = second.Navigate(localURL("ul.html"))
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	if _, err = first.FindElement(By(ID), "the-table"); err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if _, err = second.FindElement(By(TAG_NAME), "li"); err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	url, err := second.URL()
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if url != localURL("ul.html") {
+		t.Fatalf("unexpected url %v", url)
+	}
+
+	err = second.Close()
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	err = first.Switch()
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+}
+
+// required test in sequential main client test: client_test.go
+func WaitForNewWindowTest(t *testing.T) {
+	opener, err := client.CurrentWindow()
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	navigateLocal("popup.html")
+	link, err := client.FindElement(By(ID), "popup-link")
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	popup, err := client.WaitForNewWindow(ctx, link.Click, true)
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	handle, err := client.GetWindowHandle()
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if handle != popup.Handle {
+		t.Fatalf("expected to be switched to %v, current window is %v", popup.Handle, handle)
+	}
+
+	err = popup.Close()
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	err = opener.Switch()
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+}
+
+// required test in sequential main client test: client_test.go
+func WindowSwitchFrameTest(t *testing.T) {
+	navigateLocal("frames.html")
+	w, err := client.CurrentWindow()
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if _, err = client.EnterFrameIndex(0); err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	// the window's document, not the current frame's
+	if _, err = w.FindElement(By(ID), "list-frame"); err != nil {
+		t.Fatalf("%#v", err)
+	}
+}